@@ -0,0 +1,149 @@
+package flago
+
+import (
+	"github.com/cardinalby/go-struct-flags/cmdargs"
+)
+
+// ArgsError is returned by Parse() if the error is caused by a specific arg (unknown flag, invalid value,
+// multiple aliases, ...). Error() returns the message of the wrapped error
+type ArgsError struct {
+	Err error
+	// Args are the args passed to Parse(). Values of the secret flags are replaced with cmdargs.RedactedValue
+	Args cmdargs.Args
+	// Span points at the offending part of Args (the whole `@path` arg if the offending part is read from
+	// a response file). Span.Index == len(Args.Args) if a value is missing after the last arg
+	Span cmdargs.Span
+}
+
+func (e *ArgsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ArgsError) Unwrap() error {
+	return e.Err
+}
+
+// Render returns the args followed by a line with "^" characters pointing at the offending part:
+//
+//	-name x --count=abc
+//	                ^^^
+func (e *ArgsError) Render() string {
+	return e.Args.RenderSpan(e.Span)
+}
+
+// newArgsError returns ArgsError pointing at the args passed to Parse() for the `span` in fls.parsedArgs
+func (fls *FlagSet) newArgsError(err error, span cmdargs.Span) *ArgsError {
+	if fls.originalArgIndexes == nil {
+		return &ArgsError{Err: err, Args: fls.parsedArgs, Span: span}
+	}
+	originalSpan := cmdargs.Span{Index: fls.originalArgIndex(span.Index)}
+	if span.Index < len(fls.parsedArgs.Args) &&
+		originalSpan.Index < len(fls.originalArgs.Args) &&
+		fls.originalArgs.Args[originalSpan.Index] == fls.parsedArgs.Args[span.Index] {
+		// the arg is passed to Parse() as is, not read from a response file
+		originalSpan.Start, originalSpan.End = span.Start, span.End
+	}
+	return &ArgsError{Err: err, Args: fls.originalArgs, Span: originalSpan}
+}
+
+// originalArgIndex returns the index in the args passed to Parse() for the index in fls.parsedArgs.
+// len(fls.parsedArgs.Args) is mapped to len(fls.originalArgs.Args)
+func (fls *FlagSet) originalArgIndex(index int) int {
+	if fls.originalArgIndexes == nil || index < 0 {
+		return index
+	}
+	if index < len(fls.originalArgIndexes) {
+		return fls.originalArgIndexes[index]
+	}
+	return len(fls.originalArgs.Args)
+}
+
+// setParsedArgs sets fls.parsedArgs to `arguments` passed to the wrapped FlagSet and fls.originalArgs to
+// `originalArguments` passed to Parse(). `argIndexes` are indexes of `arguments` in `originalArguments`
+// or nil if they are the same
+func (fls *FlagSet) setParsedArgs(originalArguments, arguments []string, argIndexes []int) {
+	fls.parsedArgs = fls.newParsedArgs(arguments)
+	fls.originalArgIndexes = argIndexes
+	if argIndexes == nil {
+		fls.originalArgs = fls.parsedArgs
+		return
+	}
+	fls.originalArgs = fls.parsedArgs
+	fls.originalArgs.Args = make([]string, len(originalArguments))
+	copy(fls.originalArgs.Args, originalArguments)
+	for i, arg := range arguments {
+		originalIndex := argIndexes[i]
+		if redactedArg := fls.parsedArgs.Args[i]; redactedArg != arg && originalArguments[originalIndex] == arg {
+			fls.originalArgs.Args[originalIndex] = redactedArg
+		}
+	}
+}
+
+// composeArgIndexes returns indexes of args in the original args given `indexes` of intermediate
+// args in the original args (nil if they are the same) and `nextIndexes` of args in the intermediate args
+func composeArgIndexes(indexes, nextIndexes []int) []int {
+	if indexes == nil {
+		return nextIndexes
+	}
+	res := make([]int, len(nextIndexes))
+	for i, index := range nextIndexes {
+		res[i] = indexes[index]
+	}
+	return res
+}
+
+// newMultipleAliasesArgsError wraps the error into ArgsError pointing at the alias
+// that occurs later in the parsed args
+func (fls *FlagSet) newMultipleAliasesArgsError(err *MultipleAliasesError) error {
+	span1, found1 := findFlagSpan(fls.parsedArgs, err.First)
+	span2, found2 := findFlagSpan(fls.parsedArgs, err.Second)
+	if !found1 || !found2 {
+		return err
+	}
+	if span1.Index > span2.Index {
+		span2 = span1
+	}
+	return fls.newArgsError(err, span2)
+}
+
+// newParsedArgs returns Args used to point at the offending args in ArgsError.
+// Unlike Args.RedactFlags(), it keeps all args (including a dangling flag without a value)
+func (fls *FlagSet) newParsedArgs(arguments []string) cmdargs.Args {
+	args := cmdargs.NewArgs(arguments).
+		WithFlagSet(fls.FlagSet).
+		WithPrefixMatching(fls.prefixMatching).
+		WithNameNormalizer(fls.nameNormalizer)
+	if len(fls.secretFlagNames) == 0 {
+		return args
+	}
+	redacted := make([]string, len(arguments))
+	copy(redacted, arguments)
+	args.IterateEntrySpans(func(entry cmdargs.Entry, span cmdargs.EntrySpan) bool {
+		flagEntry, isFlag := entry.(cmdargs.FlagEntry)
+		if !isFlag {
+			return true
+		}
+		if !fls.isSecretFlag(flagEntry.Name()) {
+			return true
+		}
+		if valueSpan := span.FlagValue; span.TokensCount == 2 {
+			redacted[valueSpan.Index] = cmdargs.RedactedValue
+		} else if flagEntry.IsInline() {
+			redacted[valueSpan.Index] = redacted[valueSpan.Index][:valueSpan.Start] + cmdargs.RedactedValue
+		}
+		return true
+	})
+	args.Args = redacted
+	return args
+}
+
+// findFlagSpan returns the span of the name of the first occurrence of the flag
+func findFlagSpan(args cmdargs.Args, flagName string) (res cmdargs.Span, found bool) {
+	args.IterateEntrySpans(func(entry cmdargs.Entry, span cmdargs.EntrySpan) bool {
+		if flagEntry, isFlag := entry.(cmdargs.FlagEntry); isFlag && flagEntry.Name() == flagName {
+			res, found = span.FlagName, true
+		}
+		return !found
+	})
+	return res, found
+}
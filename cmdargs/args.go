@@ -10,6 +10,14 @@ type Args struct {
 	Args            []string
 	knownFlags      stdutil.FormalTagNames
 	ambiguousAsBool bool
+	prefixMatching  bool
+	nameNormalizer  NameNormalizer
+	// flagValues contains values of the flags added by WithFlagSet. Used to treat aliases sharing
+	// the same value as one flag in ResolveFlagName
+	flagValues map[string]flag.Value
+	// unresolvableFlags contains known flags that are used only to classify unknown flags
+	// (see StripUnknownFlagEntries). Passed names are not resolved to them in ResolveFlagName
+	unresolvableFlags stdutil.FormalTagNames
 }
 
 func NewArgs(args []string) Args {
@@ -25,11 +33,19 @@ func (args Args) WithAmbiguousAsBool(ambiguousAsBool bool) Args {
 
 func (args Args) WithFlagSet(flagSets ...*flag.FlagSet) Args {
 	args.knownFlags = args.knownFlags.Clone()
+	flagValues := make(map[string]flag.Value, len(args.flagValues))
+	for flagName, value := range args.flagValues {
+		flagValues[flagName] = value
+	}
 	for _, fls := range flagSets {
 		for flagName, isBoolFlag := range stdutil.GetFormalFlagNames(fls) {
 			args.knownFlags[flagName] = isBoolFlag
 		}
+		fls.VisitAll(func(f *flag.Flag) {
+			flagValues[f.Name] = f.Value
+		})
 	}
+	args.flagValues = flagValues
 	return args
 }
 
@@ -0,0 +1,53 @@
+package cmdargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseCommandLine splits the command line string into args following POSIX shell quoting rules:
+// - args are separated by whitespace (including new lines)
+// - single-quoted parts are taken literally
+// - backslash escapes `"`, `\`, `$`, "`" and new line inside double quotes
+// - backslash escapes any character outside of quotes
+// Other shell features (variables, globs, comments, ...) are not supported: the characters are taken literally
+func ParseCommandLine(s string) (Args, error) {
+	words, err := splitWords(s)
+	if err != nil {
+		return Args{}, fmt.Errorf("invalid command line at line %d: %s", err.line, err.message)
+	}
+	args := make([]string, len(words))
+	for i, w := range words {
+		args[i] = w.value
+	}
+	return NewArgs(args), nil
+}
+
+// ShellString returns space-separated args quoted (if needed) so that they can be pasted into
+// a POSIX shell. ParseCommandLine(args.ShellString()) returns the same args
+func (args Args) ShellString() string {
+	quoted := make([]string, len(args.Args))
+	for i, arg := range args.Args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote returns the arg as is if it doesn't contain special characters, otherwise
+// it is enclosed in single quotes
+func shellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if strings.IndexFunc(arg, func(r rune) bool { return !isShellSafeRune(r) }) == -1 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func isShellSafeRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9') ||
+		strings.ContainsRune("-_=+@%:,./", r)
+}
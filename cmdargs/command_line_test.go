@@ -0,0 +1,36 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	args, err := ParseCommandLine(`app -a 1 --b="x y" -c 'it''s' d\ e "" -- $HOME`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"app", "-a", "1", "--b=x y", "-c", "its", "d e", "", "--", "$HOME"}, args.Args)
+
+	args, err = ParseCommandLine("  ")
+	require.NoError(t, err)
+	require.Empty(t, args.Args)
+
+	_, err = ParseCommandLine("app\n-a \"1")
+	require.EqualError(t, err, "invalid command line at line 2: unterminated double quote")
+}
+
+func TestArgs_ShellString(t *testing.T) {
+	args := NewArgs([]string{
+		"app", "-a=1", "--path=/tmp/x.txt", "x y", "", "it's", "$HOME", "*", "~", "a\nb", `"q"`,
+	})
+	shellString := args.ShellString()
+	require.Equal(t,
+		`app -a=1 --path=/tmp/x.txt 'x y' '' 'it'\''s' '$HOME' '*' '~' 'a`+"\n"+`b' '"q"'`,
+		shellString,
+	)
+	parsed, err := ParseCommandLine(shellString)
+	require.NoError(t, err)
+	require.Equal(t, args.Args, parsed.Args)
+
+	require.Equal(t, "", NewArgs(nil).ShellString())
+}
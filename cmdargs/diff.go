@@ -0,0 +1,80 @@
+package cmdargs
+
+// ChangeKind is a kind of Change
+type ChangeKind int
+
+const (
+	// ChangeAdded means the flag is present only in the other Args
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved means the flag is present only in the original Args
+	ChangeRemoved
+	// ChangeChanged means the flag is present in both Args but with different values
+	// (or number of occurrences)
+	ChangeChanged
+)
+
+// Change describes a difference of a flag in two Args found by Diff()
+type Change struct {
+	Kind     ChangeKind
+	FlagName string
+	// OldValues contains values of all occurrences of the flag in the original Args
+	OldValues []string
+	// NewValues contains values of all occurrences of the flag in the other Args
+	NewValues []string
+}
+
+// Diff returns changes of the flags in `other` Args compared to `args`. Flags are compared by
+// values of all their occurrences in order, bool flags without values have "true" value.
+// The changes are ordered by the first occurrence of the flags in `args` followed by
+// the flags added in `other`. Unnamed args are not compared
+func (args Args) Diff(other Args) []Change {
+	oldOccurrences, oldNames := groupFlagsByName(args.getParts().flags)
+	newOccurrences, newNames := groupFlagsByName(other.getParts().flags)
+
+	var res []Change
+	for _, name := range oldNames {
+		oldValues := getFlagEntriesValues(oldOccurrences[name])
+		newFlags, isInNew := newOccurrences[name]
+		if !isInNew {
+			res = append(res, Change{Kind: ChangeRemoved, FlagName: name, OldValues: oldValues})
+			continue
+		}
+		if newValues := getFlagEntriesValues(newFlags); !equalStrings(oldValues, newValues) {
+			res = append(res, Change{Kind: ChangeChanged, FlagName: name, OldValues: oldValues, NewValues: newValues})
+		}
+	}
+	for _, name := range newNames {
+		if _, isInOld := oldOccurrences[name]; !isInOld {
+			res = append(res, Change{
+				Kind:      ChangeAdded,
+				FlagName:  name,
+				NewValues: getFlagEntriesValues(newOccurrences[name]),
+			})
+		}
+	}
+	return res
+}
+
+func getFlagEntriesValues(flags []FlagEntry) []string {
+	res := make([]string, len(flags))
+	for i, flag := range flags {
+		if flag.IsBool() && !flag.IsInline() {
+			res[i] = "true"
+		} else {
+			res[i] = flag.Value()
+		}
+	}
+	return res
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -55,7 +55,8 @@ func (args Args) iterateTokensImpl(yield func(token Token) yieldInstr) {
 
 	for i, arg := range args.Args {
 		token := Token{
-			Arg: arg,
+			Arg:   arg,
+			Index: i,
 		}
 
 		if expRole == RoleUnnamed {
@@ -97,21 +98,31 @@ func (args Args) iterateTokensImpl(yield func(token Token) yieldInstr) {
 		}
 
 		token.FlagName = parsed.flagName
+		token.FlagNameOffset = parsed.flagNameOffset
 		token.FlagValue = parsed.inlineValue
+		if parsed.hasInlineValue {
+			token.FlagValueOffset = parsed.flagNameOffset + len(parsed.flagName) + 1
+		}
 		token.Role = RoleFlag
 
 		isBoolFlag, isKnown := args.knownFlags[parsed.flagName]
+		if !isKnown && (args.prefixMatching || args.nameNormalizer != nil) {
+			if resolved, _, isResolved := args.ResolveFlagName(parsed.flagName); isResolved {
+				token.FlagName, token.PassedFlagName = resolved, parsed.flagName
+				isBoolFlag, isKnown = args.knownFlags[resolved], true
+			}
+		}
 		if isKnown {
 			token.Role |= RoleKnown
 		}
-		if parsed.inlineValue != "" {
+		if parsed.hasInlineValue {
 			token.Role |= RoleInline
 		}
 		isLastFlag := func(i int) bool {
 			argsLen := len(args.Args)
 			return i == argsLen-1 || (argsLen >= i && args.Args[i+1] == "--")
 		}
-		if !isKnown && isLastFlag(i) && parsed.inlineValue == "" {
+		if !isKnown && isLastFlag(i) && !parsed.hasInlineValue {
 			isBoolFlag = true
 		}
 		if isBoolFlag {
@@ -122,7 +133,7 @@ func (args Args) iterateTokensImpl(yield func(token Token) yieldInstr) {
 		if yieldRes.has(yieldStop) {
 			return
 		}
-		if parsed.inlineValue == "" && !isBoolFlag && (isKnown || yieldRes.has(yieldExpectValue)) {
+		if !parsed.hasInlineValue && !isBoolFlag && (isKnown || yieldRes.has(yieldExpectValue)) {
 			expRole = RoleFlagValue
 			if isKnown {
 				expRole |= RoleKnown
@@ -132,10 +143,13 @@ func (args Args) iterateTokensImpl(yield func(token Token) yieldInstr) {
 }
 
 type parsedArg struct {
-	isFlag       bool
-	isTerminator bool
-	flagName     string
-	inlineValue  string
+	isFlag         bool
+	isTerminator   bool
+	flagName       string
+	flagNameOffset int
+	inlineValue    string
+	// hasInlineValue is true if the flag contains "=" (the value can be empty: "-flag=")
+	hasInlineValue bool
 }
 
 func parseArg(arg string) (res parsedArg) {
@@ -159,9 +173,11 @@ func parseArg(arg string) (res parsedArg) {
 	if equalsSignIndex > 0 {
 		res.flagName = flagNameValue[:equalsSignIndex]
 		res.inlineValue = flagNameValue[equalsSignIndex+1:]
+		res.hasInlineValue = true
 	} else {
 		res.flagName = flagNameValue
 	}
+	res.flagNameOffset = argFlagNameStartIndex
 	res.isFlag = true
 	return res
 }
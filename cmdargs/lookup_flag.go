@@ -46,3 +46,58 @@ func (args Args) UpsertFlag(
 	res.knownFlags[insert.name] = insert.IsBool()
 	return res
 }
+
+// FlagOccurrence is a flag found by LookupAllFlags() or LookupFlagAliases()
+type FlagOccurrence struct {
+	Flag FlagEntry
+	// EntryIndex is an index of the flag among the entries yielded by IterateEntries(). Can be passed to ReplaceFlagAt()
+	EntryIndex int
+	// TokenIndex is an index of the first arg of the flag in Args.Args
+	TokenIndex int
+}
+
+// LookupAllFlags returns all occurrences of the flag in order of appearance
+func (args Args) LookupAllFlags(flagName string) []FlagOccurrence {
+	return args.LookupFlagAliases(flagName)
+}
+
+// LookupFlagAliases returns all occurrences of the flags with any of the given names (aliases of the same flag)
+// in order of appearance
+func (args Args) LookupFlagAliases(flagNames ...string) (res []FlagOccurrence) {
+	entryIndex := 0
+	args.IterateEntrySpans(func(entry Entry, span EntrySpan) bool {
+		if f, isFlag := entry.(FlagEntry); isFlag {
+			for _, flagName := range flagNames {
+				if f.Name() == flagName {
+					res = append(res, FlagOccurrence{
+						Flag:       f,
+						EntryIndex: entryIndex,
+						TokenIndex: span.Index,
+					})
+					break
+				}
+			}
+		}
+		entryIndex++
+		return true
+	})
+	return res
+}
+
+// ReplaceFlagAt replaces the flag entry having the given index (see FlagOccurrence.EntryIndex) with `entry`.
+// If `entry` is nil, the flag is deleted. Returns false if there is no flag entry with the given index
+func (args Args) ReplaceFlagAt(entryIndex int, entry Entry) (res Args, replaced bool) {
+	i := 0
+	res = args.MapEntries(func(e Entry) Entry {
+		defer func() { i++ }()
+		if _, isFlag := e.(FlagEntry); isFlag && i == entryIndex {
+			replaced = true
+			return entry
+		}
+		return e
+	})
+	if !replaced {
+		return args, false
+	}
+	return res, true
+}
@@ -179,3 +179,41 @@ func TestArgs_UpsertFlag(t *testing.T) {
 		}, args.knownFlags)
 	})
 }
+
+func TestArgs_LookupAllFlags(t *testing.T) {
+	args := NewArgs([]string{"-H", "a", "-v", "--header=b", "-H", "c", "--", "-H", "d"}).
+		WithKnownFlags(stdutil.FormalTagNames{"v": true})
+
+	require.Equal(t, []FlagOccurrence{
+		{Flag: NewFlagEntry("H", "a"), EntryIndex: 0, TokenIndex: 0},
+		{Flag: NewFlagEntry("H", "c"), EntryIndex: 3, TokenIndex: 4},
+	}, args.LookupAllFlags("H"))
+
+	require.Equal(t, []FlagOccurrence{
+		{Flag: NewFlagEntry("H", "a"), EntryIndex: 0, TokenIndex: 0},
+		{Flag: NewFlagEntry("header", "b").WithInline(true).WithDoubleDashes(true), EntryIndex: 2, TokenIndex: 3},
+		{Flag: NewFlagEntry("H", "c"), EntryIndex: 3, TokenIndex: 4},
+	}, args.LookupFlagAliases("H", "header"))
+
+	require.Nil(t, args.LookupAllFlags("x"))
+}
+
+func TestArgs_ReplaceFlagAt(t *testing.T) {
+	args := NewArgs([]string{"-H", "a", "-v", "--header=b", "-H", "c", "--", "-H", "d"}).
+		WithKnownFlags(stdutil.FormalTagNames{"v": true})
+
+	occurrences := args.LookupFlagAliases("H", "header")
+	res, replaced := args.ReplaceFlagAt(occurrences[1].EntryIndex, occurrences[1].Flag.WithName("H").WithValue("x"))
+	require.True(t, replaced)
+	require.Equal(t, []string{"-H", "a", "-v", "--H=x", "-H", "c", "--", "-H", "d"}, res.Args)
+
+	res, replaced = res.ReplaceFlagAt(occurrences[0].EntryIndex, nil)
+	require.True(t, replaced)
+	require.Equal(t, []string{"-v", "--H=x", "-H", "c", "--", "-H", "d"}, res.Args)
+
+	for _, entryIndex := range []int{-1, 4, 5, 100} {
+		res, replaced = args.ReplaceFlagAt(entryIndex, NewFlagEntry("x", "1"))
+		require.False(t, replaced)
+		require.Equal(t, args.Args, res.Args)
+	}
+}
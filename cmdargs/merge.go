@@ -0,0 +1,138 @@
+package cmdargs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MergePolicy defines how Merge() handles flags present in both Args
+type MergePolicy int
+
+const (
+	// MergeReplace replaces all occurrences of the flag in the base Args with all its occurrences in the
+	// override Args (placed at the position of the first base occurrence).
+	// Unnamed args of the override Args (if any) replace the base unnamed args
+	MergeReplace MergePolicy = iota
+	// MergeAppend keeps base occurrences of the flag and inserts override occurrences after the last
+	// base one (for repeatable flags). Unnamed args of the override Args are appended to the base ones
+	MergeAppend
+	// MergeError makes Merge() return an error containing ErrMergeConflict if a flag is present in both Args
+	// or both Args have unnamed args
+	MergeError
+)
+
+// ErrMergeConflict is returned by Merge() with MergeError policy
+var ErrMergeConflict = errors.New("merge conflict")
+
+// argsParts contains flags and unnamed args of Args
+type argsParts struct {
+	flags         []FlagEntry
+	unnamedArgs   []string
+	hasTerminator bool
+}
+
+func (args Args) getParts() (res argsParts) {
+	args.IterateEntries(func(entry Entry) bool {
+		switch e := entry.(type) {
+		case FlagEntry:
+			res.flags = append(res.flags, e)
+		case TerminatorEntry:
+			res.hasTerminator = true
+		case UnnamedArgsEntry:
+			res.unnamedArgs = append(res.unnamedArgs, e...)
+		}
+		return true
+	})
+	return res
+}
+
+// groupFlagsByName returns occurrences of flags by their names and the names in order of the first occurrence
+func groupFlagsByName(flags []FlagEntry) (occurrences map[string][]FlagEntry, names []string) {
+	occurrences = make(map[string][]FlagEntry)
+	for _, flag := range flags {
+		if _, has := occurrences[flag.Name()]; !has {
+			names = append(names, flag.Name())
+		}
+		occurrences[flag.Name()] = append(occurrences[flag.Name()], flag)
+	}
+	return occurrences, names
+}
+
+// Merge applies flags and unnamed args of `override` on top of `args` according to the `policy`.
+// Flags of `override` that are not present in `args` are added after the flags of `args`.
+// Each Args is tokenized using its own known flags, the result has known flags of both
+// (`override` takes precedence)
+func (args Args) Merge(override Args, policy MergePolicy) (Args, error) {
+	base := args.getParts()
+	over := override.getParts()
+	baseOccurrences, _ := groupFlagsByName(base.flags)
+	overOccurrences, overNames := groupFlagsByName(over.flags)
+
+	var flags []FlagEntry
+	switch policy {
+	case MergeReplace:
+		isReplaced := make(map[string]bool)
+		for _, flag := range base.flags {
+			overFlags, isOverridden := overOccurrences[flag.Name()]
+			switch {
+			case !isOverridden:
+				flags = append(flags, flag)
+			case !isReplaced[flag.Name()]:
+				flags = append(flags, overFlags...)
+				isReplaced[flag.Name()] = true
+			}
+		}
+	case MergeAppend:
+		seenCount := make(map[string]int)
+		for _, flag := range base.flags {
+			flags = append(flags, flag)
+			seenCount[flag.Name()]++
+			if seenCount[flag.Name()] == len(baseOccurrences[flag.Name()]) {
+				flags = append(flags, overOccurrences[flag.Name()]...)
+			}
+		}
+	case MergeError:
+		for _, name := range overNames {
+			if _, isInBase := baseOccurrences[name]; isInBase {
+				return Args{}, fmt.Errorf(`%w: flag "%s" is present in both args`, ErrMergeConflict, name)
+			}
+		}
+		if len(base.unnamedArgs) > 0 && len(over.unnamedArgs) > 0 {
+			return Args{}, fmt.Errorf(`%w: both args have unnamed args`, ErrMergeConflict)
+		}
+		flags = append(flags, base.flags...)
+	default:
+		return Args{}, fmt.Errorf("unknown merge policy %d", policy)
+	}
+	for _, flag := range over.flags {
+		if _, isInBase := baseOccurrences[flag.Name()]; !isInBase {
+			flags = append(flags, flag)
+		}
+	}
+
+	unnamedArgs, hasTerminator := base.unnamedArgs, base.hasTerminator
+	if len(over.unnamedArgs) > 0 {
+		if policy == MergeAppend {
+			unnamedArgs = append(unnamedArgs[:len(unnamedArgs):len(unnamedArgs)], over.unnamedArgs...)
+			hasTerminator = hasTerminator || over.hasTerminator
+		} else {
+			unnamedArgs, hasTerminator = over.unnamedArgs, over.hasTerminator
+		}
+	}
+
+	res := Args{
+		knownFlags:      args.knownFlags.Clone(),
+		ambiguousAsBool: args.ambiguousAsBool,
+	}
+	for flagName, isBoolFlag := range override.knownFlags {
+		res.knownFlags[flagName] = isBoolFlag
+	}
+	for _, flag := range flags {
+		res.Args = append(res.Args, flag.TokenStrings()...)
+	}
+	if hasTerminator || (len(unnamedArgs) > 0 && parseArg(unnamedArgs[0]).isFlag) {
+		res.Args = append(res.Args, NewTerminatorEntry().TokenStrings()...)
+	}
+	res.Args = append(res.Args, unnamedArgs...)
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs_Merge(t *testing.T) {
+	knownFlags := stdutil.FormalTagNames{"v": true, "race": true}
+	base := NewArgs([]string{"-H", "a", "-v", "--tag=x", "-H", "b", "-o", "out", "file1"}).
+		WithKnownFlags(knownFlags)
+	override := NewArgs([]string{"-H", "c", "-race", "-o=bin", "--", "-file2"}).
+		WithKnownFlags(knownFlags)
+
+	testCases := []struct {
+		name    string
+		policy  MergePolicy
+		expArgs []string
+	}{
+		{
+			name:    "replace",
+			policy:  MergeReplace,
+			expArgs: []string{"-H", "c", "-v", "--tag=x", "-o=bin", "-race", "--", "-file2"},
+		},
+		{
+			name:   "append",
+			policy: MergeAppend,
+			expArgs: []string{
+				"-H", "a", "-v", "--tag=x", "-H", "b", "-H", "c", "-o", "out", "-o=bin", "-race",
+				"--", "file1", "-file2",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			merged, err := base.Merge(override, testCase.policy)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expArgs, merged.Args)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, err := base.Merge(override, MergeError)
+		require.ErrorIs(t, err, ErrMergeConflict)
+		require.ErrorContains(t, err, `flag "H"`)
+
+		_, err = base.Merge(NewArgs([]string{"-x", "1", "file2"}), MergeError)
+		require.ErrorIs(t, err, ErrMergeConflict)
+
+		merged, err := base.Merge(NewArgs([]string{"-x", "1"}), MergeError)
+		require.NoError(t, err)
+		require.Equal(t, []string{"-H", "a", "-v", "--tag=x", "-H", "b", "-o", "out", "-x", "1", "file1"}, merged.Args)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		merged, err := NewArgs(nil).Merge(base, MergeReplace)
+		require.NoError(t, err)
+		require.Equal(t, base.Args, merged.Args)
+
+		merged, err = base.Merge(NewArgs(nil), MergeAppend)
+		require.NoError(t, err)
+		require.Equal(t, base.Args, merged.Args)
+	})
+}
+
+func TestArgs_Diff(t *testing.T) {
+	knownFlags := stdutil.FormalTagNames{"v": true, "race": true}
+	args := NewArgs([]string{"-H", "a", "-v", "--tag=x", "-H", "b", "-o", "out", "-race", "file1"}).
+		WithKnownFlags(knownFlags)
+	other := NewArgs([]string{"-o", "out", "-H", "a", "-v=true", "-n", "1", "-race=false", "file2"}).
+		WithKnownFlags(knownFlags)
+
+	require.Equal(t, []Change{
+		{Kind: ChangeChanged, FlagName: "H", OldValues: []string{"a", "b"}, NewValues: []string{"a"}},
+		{Kind: ChangeRemoved, FlagName: "tag", OldValues: []string{"x"}},
+		{Kind: ChangeChanged, FlagName: "race", OldValues: []string{"true"}, NewValues: []string{"false"}},
+		{Kind: ChangeAdded, FlagName: "n", NewValues: []string{"1"}},
+	}, args.Diff(other))
+	require.Nil(t, args.Diff(args))
+}
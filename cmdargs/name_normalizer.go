@@ -0,0 +1,22 @@
+package cmdargs
+
+import (
+	"strings"
+)
+
+// NameNormalizer converts a flag name to the form used to compare it with known flag names
+type NameNormalizer func(name string) string
+
+// FoldCaseAndSeparators is NameNormalizer that makes names case-insensitive and treats "_" and "-"
+// as equal: `--Log_Level` matches `--log-level`
+func FoldCaseAndSeparators(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// WithNameNormalizer returns Args where a flag name that is not known is resolved to the known flag
+// having the same name after normalization by `normalizer` (see ResolveFlagName). Exact matches always win.
+// Names matching multiple known flags are treated as unknown flags. Nil `normalizer` disables normalization
+func (args Args) WithNameNormalizer(normalizer NameNormalizer) Args {
+	args.nameNormalizer = normalizer
+	return args
+}
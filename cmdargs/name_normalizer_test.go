@@ -0,0 +1,50 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldCaseAndSeparators(t *testing.T) {
+	require.Equal(t, "log-level", FoldCaseAndSeparators("Log_Level"))
+	require.Equal(t, "log-level", FoldCaseAndSeparators("log-level"))
+}
+
+func TestNameNormalizer(t *testing.T) {
+	knownFlags := stdutil.FormalTagNames{
+		"log-level": false,
+		"Debug":     true,
+		"debug":     true,
+		"verbose":   true,
+	}
+	args := NewArgs([]string{"--Log_Level", "info", "-debug", "-DEBUG", "-VERB"}).
+		WithKnownFlags(knownFlags).
+		WithNameNormalizer(FoldCaseAndSeparators)
+
+	var tokens []Token
+	args.IterateTokens(func(token Token) bool {
+		tokens = append(tokens, withoutPosition(token))
+		return true
+	})
+	require.Equal(t, []Token{
+		{
+			Arg: "--Log_Level", FlagName: "log-level", PassedFlagName: "Log_Level",
+			Role: RoleFlag | RoleKnown,
+		},
+		{Arg: "info", FlagValue: "info", Role: RoleFlagValue | RoleKnown},
+		{Arg: "-debug", FlagName: "debug", Role: RoleFlag | RoleKnown | RoleBoolFlag},
+		// matches both "Debug" and "debug", treated as unknown flag followed by value
+		{Arg: "-DEBUG", FlagName: "DEBUG", Role: RoleFlag},
+		{Arg: "-VERB", FlagValue: "-VERB", Role: RoleFlagValue},
+	}, tokens)
+
+	resolved, _, isResolved := args.WithPrefixMatching(true).ResolveFlagName("VERB")
+	require.True(t, isResolved)
+	require.Equal(t, "verbose", resolved)
+
+	_, candidates, isResolved := args.ResolveFlagName("DEBUG")
+	require.False(t, isResolved)
+	require.Equal(t, []string{"Debug", "debug"}, candidates)
+}
@@ -0,0 +1,63 @@
+package cmdargs
+
+import (
+	"flag"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+)
+
+// FlagsConsumer describes a named set of flags consumed by a tool. Used in Partition()
+type FlagsConsumer struct {
+	Name       string
+	KnownFlags stdutil.FormalTagNames
+}
+
+// NewFlagSetConsumer returns FlagsConsumer with the name and the flags of the given FlagSet
+func NewFlagSetConsumer(flagSet *flag.FlagSet) FlagsConsumer {
+	return FlagsConsumer{
+		Name:       flagSet.Name(),
+		KnownFlags: stdutil.GetFormalFlagNames(flagSet),
+	}
+}
+
+// Partition splits args into parts containing flags known to the consumers (keys of `parts` are consumer
+// names) and leftovers containing unknown flags, unnamed args and "--" terminator.
+// If a flag is known to several consumers, it goes to the first of them. Types of the flags known to
+// the consumers are used to classify ambiguous flags (the first consumer takes precedence),
+// other flags are classified according to the known flags of `args`.
+// The order of args and the style of flags (inline values, double dashes) are preserved in all parts.
+// Parts of the consumers that didn't get any flags contain nil Args
+func (args Args) Partition(consumers ...FlagsConsumer) (parts map[string]Args, leftovers Args) {
+	c := args
+	for i := len(consumers) - 1; i >= 0; i-- {
+		c = c.WithKnownFlags(consumers[i].KnownFlags)
+	}
+
+	parts = make(map[string]Args, len(consumers))
+	for _, consumer := range consumers {
+		if _, has := parts[consumer.Name]; !has {
+			parts[consumer.Name] = Args{
+				knownFlags:      consumer.KnownFlags.Clone(),
+				ambiguousAsBool: args.ambiguousAsBool,
+			}
+		}
+	}
+	leftovers.knownFlags = args.knownFlags
+	leftovers.ambiguousAsBool = args.ambiguousAsBool
+
+	c.IterateEntries(func(entry Entry) bool {
+		if f, isFlag := entry.(FlagEntry); isFlag {
+			for _, consumer := range consumers {
+				if _, isKnown := consumer.KnownFlags[f.Name()]; isKnown {
+					part := parts[consumer.Name]
+					part.Args = append(part.Args, entry.TokenStrings()...)
+					parts[consumer.Name] = part
+					return true
+				}
+			}
+		}
+		leftovers.Args = append(leftovers.Args, entry.TokenStrings()...)
+		return true
+	})
+	return parts, leftovers
+}
@@ -0,0 +1,42 @@
+package cmdargs
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs_Partition(t *testing.T) {
+	goBuild := flag.NewFlagSet("go", flag.ContinueOnError)
+	goBuild.Bool("race", false, "")
+	goBuild.String("o", "", "")
+	goBuild.Bool("v", false, "")
+
+	linter := FlagsConsumer{
+		Name:       "linter",
+		KnownFlags: stdutil.FormalTagNames{"config": false, "fix": true, "v": false},
+	}
+
+	args := NewArgs([]string{
+		"-name", "x", "-race", "--config=a.yml", "-v", "-o", "out", "--fix", "-unknown", "-fix=false",
+		"--", "-file", "file2",
+	}).WithKnownFlags(stdutil.FormalTagNames{"name": false})
+
+	parts, leftovers := args.Partition(NewFlagSetConsumer(goBuild), linter, FlagsConsumer{Name: "empty"})
+	require.Equal(t, []string{"-race", "-v", "-o", "out"}, parts["go"].Args)
+	require.Equal(t, []string{"--config=a.yml", "--fix"}, parts["linter"].Args)
+	require.Nil(t, parts["empty"].Args)
+	// unknown ambiguous flag consumes the next arg as its value
+	require.Equal(t, []string{"-name", "x", "-unknown", "-fix=false", "--", "-file", "file2"}, leftovers.Args)
+
+	// parts keep types of the consumer flags
+	flagEntry, has := parts["linter"].LookupFlag("fix")
+	require.True(t, has)
+	require.True(t, flagEntry.IsBool())
+
+	parts, leftovers = NewArgs([]string{"-a", "b", "c", "-race"}).Partition(NewFlagSetConsumer(goBuild))
+	require.Nil(t, parts["go"].Args)
+	require.Equal(t, []string{"-a", "b", "c", "-race"}, leftovers.Args)
+}
@@ -0,0 +1,89 @@
+package cmdargs
+
+import (
+	"flag"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WithPrefixMatching returns Args where a flag name that is not known but is a unique prefix of a known
+// flag name is resolved to the known flag (`--verb` is treated as `--verbose`), similar to GNU getopt_long.
+// Exact matches always win. Ambiguous prefixes are treated as unknown flags
+func (args Args) WithPrefixMatching(enabled bool) Args {
+	args.prefixMatching = enabled
+	return args
+}
+
+// ResolveFlagName returns the known flag name matching `name`:
+// - `name` itself if it's known
+// - the only known flag name that is equal to `name` after normalization (see WithNameNormalizer)
+// - the only known flag name starting with `name` if prefix matching is enabled (see WithPrefixMatching).
+// Names are compared after normalization if the normalizer is set.
+// Aliases sharing the same flag.Value (see WithFlagSet) are treated as one flag, the first of them in
+// sorted order is returned.
+// If `name` matches multiple known flags, `isResolved` is false and `candidates` contains them sorted
+func (args Args) ResolveFlagName(name string) (resolved string, candidates []string, isResolved bool) {
+	if _, isKnown := args.knownFlags[name]; isKnown {
+		return name, nil, true
+	}
+	if (!args.prefixMatching && args.nameNormalizer == nil) || name == "" {
+		return "", nil, false
+	}
+	normalize := args.nameNormalizer
+	if normalize == nil {
+		normalize = func(name string) string { return name }
+	}
+	normalizedName := normalize(name)
+	var equal, prefixed []string
+	for flagName := range args.knownFlags {
+		if _, isUnresolvable := args.unresolvableFlags[flagName]; isUnresolvable {
+			continue
+		}
+		normalizedFlagName := normalize(flagName)
+		if normalizedFlagName == normalizedName {
+			equal = append(equal, flagName)
+		} else if args.prefixMatching && strings.HasPrefix(normalizedFlagName, normalizedName) {
+			prefixed = append(prefixed, flagName)
+		}
+	}
+	// equal names win over prefixes
+	if len(equal) == 0 {
+		equal = prefixed
+	}
+	sort.Strings(equal)
+	equal = args.removeAliases(equal)
+	if len(equal) == 1 {
+		return equal[0], nil, true
+	}
+	return "", equal, false
+}
+
+// removeAliases keeps only the first of the sorted flag names sharing the same flag.Value
+func (args Args) removeAliases(flagNames []string) []string {
+	if len(flagNames) < 2 || len(args.flagValues) == 0 {
+		return flagNames
+	}
+	var res []string
+	for _, flagName := range flagNames {
+		isAlias := false
+		for _, added := range res {
+			if isSameFlagValue(args.flagValues[flagName], args.flagValues[added]) {
+				isAlias = true
+				break
+			}
+		}
+		if !isAlias {
+			res = append(res, flagName)
+		}
+	}
+	return res
+}
+
+func isSameFlagValue(a, b flag.Value) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	aType := reflect.TypeOf(a)
+	return aType == reflect.TypeOf(b) && aType.Comparable() && a == b
+}
@@ -0,0 +1,100 @@
+package cmdargs
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixMatching(t *testing.T) {
+	knownFlags := stdutil.FormalTagNames{
+		"verbose":  true,
+		"verbatim": false,
+		"name":     false,
+		"n":        true,
+	}
+	args := NewArgs([]string{"--verbo", "-na", "x", "-n", "--verb=1", "-ver", "y"}).WithKnownFlags(knownFlags)
+
+	var entries []Entry
+	args.WithPrefixMatching(true).IterateEntries(func(entry Entry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	require.Equal(t, []Entry{
+		NewBoolFlagEntry("verbose", "").WithDoubleDashes(true),
+		NewFlagEntry("name", "x"),
+		NewBoolFlagEntry("n", ""),
+		NewFlagEntry("verb", "1").WithInline(true).WithDoubleDashes(true),
+		NewFlagEntry("ver", "y"),
+	}, entries)
+
+	var spans []Span
+	args.WithPrefixMatching(true).IterateEntrySpans(func(entry Entry, span EntrySpan) bool {
+		spans = append(spans, span.FlagName)
+		return true
+	})
+	require.Equal(t, Span{Index: 1, Start: 1, End: 3}, spans[1])
+
+	var tokens []Token
+	args.WithPrefixMatching(true).IterateTokens(func(token Token) bool {
+		tokens = append(tokens, token)
+		return true
+	})
+	require.Equal(t, "verbose", tokens[0].FlagName)
+	require.Equal(t, "verbo", tokens[0].PassedFlagName)
+	require.Equal(t, "n", tokens[3].FlagName)
+	require.Equal(t, "", tokens[3].PassedFlagName)
+
+	entries = nil
+	args.IterateEntries(func(entry Entry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	require.Equal(t, NewFlagEntry("verbo", "-na").WithDoubleDashes(true), entries[0])
+}
+
+func TestResolveFlagName(t *testing.T) {
+	args := NewArgs(nil).WithKnownFlags(stdutil.FormalTagNames{"verbose": true, "verbatim": false, "v": true})
+
+	resolved, candidates, isResolved := args.ResolveFlagName("verbo")
+	require.False(t, isResolved)
+	require.Empty(t, resolved)
+	require.Nil(t, candidates)
+
+	args = args.WithPrefixMatching(true)
+	for name, expected := range map[string]string{"v": "v", "verbo": "verbose", "verba": "verbatim"} {
+		resolved, candidates, isResolved = args.ResolveFlagName(name)
+		require.True(t, isResolved, name)
+		require.Equal(t, expected, resolved, name)
+		require.Nil(t, candidates, name)
+	}
+
+	resolved, candidates, isResolved = args.ResolveFlagName("verb")
+	require.False(t, isResolved)
+	require.Empty(t, resolved)
+	require.Equal(t, []string{"verbatim", "verbose"}, candidates)
+
+	_, candidates, isResolved = args.ResolveFlagName("x")
+	require.False(t, isResolved)
+	require.Nil(t, candidates)
+}
+
+func TestResolveFlagNameAliases(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", false, "")
+	fs.BoolVar(verbose, "verbosity", false, "")
+	fs.Bool("verbatim", false, "")
+	args := NewArgs(nil).WithFlagSet(fs).WithPrefixMatching(true)
+
+	resolved, candidates, isResolved := args.ResolveFlagName("verbo")
+	require.True(t, isResolved)
+	require.Equal(t, "verbose", resolved)
+	require.Nil(t, candidates)
+
+	resolved, candidates, isResolved = args.ResolveFlagName("verb")
+	require.False(t, isResolved)
+	require.Empty(t, resolved)
+	require.Equal(t, []string{"verbatim", "verbose"}, candidates)
+}
@@ -0,0 +1,20 @@
+package cmdargs
+
+// RedactedValue replaces values of the flags in RedactFlags()
+const RedactedValue = "******"
+
+// RedactFlags returns Args where values of all occurrences of the flags with the given names are
+// replaced with RedactedValue. Bool flags without values are left as is
+func (args Args) RedactFlags(flagNames ...string) Args {
+	isRedacted := make(map[string]bool, len(flagNames))
+	for _, flagName := range flagNames {
+		isRedacted[flagName] = true
+	}
+	return args.MapFlags(func(flag FlagEntry) Entry {
+		if !isRedacted[flag.Name()] || (flag.IsBool() && !flag.IsInline()) {
+			return flag
+		}
+		flag.value = RedactedValue
+		return flag
+	})
+}
@@ -0,0 +1,20 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs_RedactFlags(t *testing.T) {
+	args := NewArgs([]string{
+		"-password", "abc", "--token=def", "-b", "-s", "ghi", "-b=true", "--password", "jkl", "rem",
+	}).WithKnownFlags(stdutil.FormalTagNames{
+		"b": true,
+	})
+	require.Equal(t, []string{
+		"-password", "******", "--token=******", "-b", "-s", "ghi", "-b=******", "--password", "******", "rem",
+	}, args.RedactFlags("password", "token", "b").Args)
+	require.Equal(t, args.Args, args.RedactFlags().Args)
+}
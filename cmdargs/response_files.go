@@ -0,0 +1,118 @@
+package cmdargs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrResponseFilesCycle is wrapped by ResponseFileError if a response file includes itself
+// directly or through other response files
+var ErrResponseFilesCycle = errors.New("response files cycle")
+
+// ResponseFileError is returned by ExpandResponseFiles() if a response file can't be expanded
+type ResponseFileError struct {
+	FileName string
+	// Line is a 1-based line number in the file or 0 if the error is not related to a specific line
+	Line int
+	Err  error
+}
+
+func (e *ResponseFileError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("response file %s: %s", e.FileName, e.Err)
+	}
+	return fmt.Sprintf("response file %s:%d: %s", e.FileName, e.Line, e.Err)
+}
+
+func (e *ResponseFileError) Unwrap() error {
+	return e.Err
+}
+
+// ExpandResponseFiles replaces each `@path` arg with args read from the file at `path`. The file content
+// is split by whitespace (including new lines) following POSIX shell quoting rules: single and double
+// quotes and backslash escapes are supported. Args read from the file are expanded recursively.
+// Args after the first "--" arg are not expanded.
+// Returns *ResponseFileError if a file can't be read, has invalid syntax or includes itself
+func ExpandResponseFiles(args []string) ([]string, error) {
+	res, _, err := ExpandResponseFilesWithIndexes(args)
+	return res, err
+}
+
+// ExpandResponseFilesWithIndexes is the same as ExpandResponseFiles but also returns `argIndexes`:
+// argIndexes[i] is the index of the arg in `args` that res[i] is (or is read from, for the args read
+// from a response file)
+func ExpandResponseFilesWithIndexes(args []string) (res []string, argIndexes []int, err error) {
+	e := responseFilesExpander{argIndexes: make([]int, 0, len(args))}
+	for i, arg := range args {
+		e.argIndex = i
+		if err := e.expand([]word{{value: arg}}, "", nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return e.res, e.argIndexes, nil
+}
+
+type responseFilesExpander struct {
+	res        []string
+	argIndexes []int
+	// argIndex is the index of the original arg being expanded
+	argIndex     int
+	isTerminated bool
+}
+
+// expand expands `words` read from `fileName` ("" for the original args).
+// `stack` contains absolute paths of the files being expanded
+func (e *responseFilesExpander) expand(words []word, fileName string, stack []string) error {
+	for _, w := range words {
+		if e.isTerminated || len(w.value) < 2 || w.value[0] != '@' {
+			if w.value == "--" {
+				e.isTerminated = true
+			}
+			e.res = append(e.res, w.value)
+			e.argIndexes = append(e.argIndexes, e.argIndex)
+			continue
+		}
+		path := w.value[1:]
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return newReferencedFileError(fileName, w, path, err)
+		}
+		for _, expandedPath := range stack {
+			if expandedPath == absPath {
+				return &ResponseFileError{
+					FileName: fileName,
+					Line:     w.line,
+					Err:      fmt.Errorf(`%w: "%s" is already being expanded`, ErrResponseFilesCycle, path),
+				}
+			}
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return newReferencedFileError(fileName, w, path, err)
+		}
+		fileWords, splitErr := splitWords(string(content))
+		if splitErr != nil {
+			return &ResponseFileError{FileName: path, Line: splitErr.line, Err: errors.New(splitErr.message)}
+		}
+		if err := e.expand(fileWords, path, append(stack[:len(stack):len(stack)], absPath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newReferencedFileError returns ResponseFileError for the file at `path` that can't be read.
+// If the file is referenced by `w` from another response file, the error points to the line of `w`
+// in `fileName` and wraps the path in Err
+func newReferencedFileError(fileName string, w word, path string, err error) *ResponseFileError {
+	if fileName == "" {
+		return &ResponseFileError{FileName: path, Err: err}
+	}
+	return &ResponseFileError{
+		FileName: fileName,
+		Line:     w.line,
+		Err:      fmt.Errorf(`"%s": %w`, path, err),
+	}
+}
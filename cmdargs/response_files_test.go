@@ -0,0 +1,71 @@
+package cmdargs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeResponseFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	nested := writeResponseFile(t, dir, "nested.txt", "-n 'nested value'\n")
+	main := writeResponseFile(t, dir, "main.txt", "-a 1\n  \"-b=x y\" @"+nested+"\n-- @"+nested)
+	terminated := writeResponseFile(t, dir, "terminated.txt", "@"+nested)
+
+	args, err := ExpandResponseFiles([]string{"-c", "@" + main, "@" + nested, "@", "x"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"-c", "-a", "1", "-b=x y", "-n", "nested value", "--", "@" + nested, "@" + nested, "@", "x",
+	}, args)
+
+	args, argIndexes, err := ExpandResponseFilesWithIndexes([]string{"-c", "@" + main, "x"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"-c", "-a", "1", "-b=x y", "-n", "nested value", "--", "@" + nested, "x"}, args)
+	require.Equal(t, []int{0, 1, 1, 1, 1, 1, 1, 1, 2}, argIndexes)
+
+	args, err = ExpandResponseFiles([]string{"@" + terminated, "--", "@" + terminated})
+	require.NoError(t, err)
+	require.Equal(t, []string{"-n", "nested value", "--", "@" + terminated}, args)
+
+	args, err = ExpandResponseFiles(nil)
+	require.NoError(t, err)
+	require.Nil(t, args)
+}
+
+func TestExpandResponseFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := writeResponseFile(t, dir, "invalid.txt", "-a\n-b 'c")
+	_, err := ExpandResponseFiles([]string{"@" + invalid})
+	require.EqualError(t, err, "response file "+invalid+":2: unterminated single quote")
+
+	missing := filepath.Join(dir, "missing.txt")
+	_, err = ExpandResponseFiles([]string{"@" + missing})
+	var responseFileErr *ResponseFileError
+	require.True(t, errors.As(err, &responseFileErr))
+	require.Equal(t, missing, responseFileErr.FileName)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	referencing := writeResponseFile(t, dir, "referencing.txt", "-a\n-b @"+missing)
+	_, err = ExpandResponseFiles([]string{"@" + referencing})
+	require.True(t, errors.As(err, &responseFileErr))
+	require.Equal(t, referencing, responseFileErr.FileName)
+	require.Equal(t, 2, responseFileErr.Line)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	require.ErrorContains(t, err, "response file "+referencing+`:2: "`+missing+`": `)
+
+	first := filepath.Join(dir, "first.txt")
+	second := writeResponseFile(t, dir, "second.txt", "-b\n@"+first)
+	writeResponseFile(t, dir, "first.txt", "-a @"+second)
+	_, err = ExpandResponseFiles([]string{"@" + first})
+	require.ErrorIs(t, err, ErrResponseFilesCycle)
+	require.EqualError(t, err, "response file "+second+`:2: response files cycle: "`+first+`" is already being expanded`)
+}
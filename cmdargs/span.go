@@ -0,0 +1,140 @@
+package cmdargs
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Span is a part of an arg in Args.Args
+type Span struct {
+	// Index is the index of the arg in Args.Args
+	Index int
+	// Start and End are byte offsets of the part in the arg. If Start == End, the span covers the whole arg
+	Start int
+	End   int
+}
+
+// EntrySpan describes the position of an Entry in Args.Args
+type EntrySpan struct {
+	// Index is the index of the first arg of the entry in Args.Args
+	Index int
+	// TokensCount is the number of args of the entry
+	TokensCount int
+	// FlagName is the span of the flag name (without dashes). Zero for non-flag entries
+	FlagName Span
+	// FlagValue is the span of the flag value: a part of the same arg for inline flags or the next arg.
+	// Zero for non-flag entries and bool flags without values
+	FlagValue Span
+}
+
+// IterateEntrySpans is the same as IterateEntries but also yields positions of the entries in Args.Args
+func (args Args) IterateEntrySpans(yield func(entry Entry, span EntrySpan) (getNext bool)) {
+	var unnamedArgs UnnamedArgsEntry
+	unnamedArgsIndex := 0
+	var prevFlagNameToken Token
+	getFlagNameSpan := func(token Token) Span {
+		nameLen := len(token.FlagName)
+		if token.PassedFlagName != "" {
+			nameLen = len(token.PassedFlagName)
+		}
+		return Span{
+			Index: token.Index,
+			Start: token.FlagNameOffset,
+			End:   token.FlagNameOffset + nameLen,
+		}
+	}
+	args.IterateTokens(func(token Token) bool {
+		switch {
+		case token.Role.Has(RoleFlag):
+			isBool := token.Role.Has(RoleBoolFlag)
+			isInline := token.Role.Has(RoleInline)
+			if !isInline && !isBool {
+				prevFlagNameToken = token
+				return true
+			}
+			span := EntrySpan{
+				Index:       token.Index,
+				TokensCount: 1,
+				FlagName:    getFlagNameSpan(token),
+			}
+			if isInline {
+				span.FlagValue = Span{
+					Index: token.Index,
+					Start: token.FlagValueOffset,
+					End:   len(token.Arg),
+				}
+			}
+			return yield(FlagEntry{
+				name:           token.FlagName,
+				value:          token.FlagValue,
+				isInline:       isInline,
+				isDoubleDashed: token.Arg[1] == '-',
+				isBool:         isBool,
+			}, span)
+		case token.Role.Has(RoleFlagValue):
+			yieldRes := yield(FlagEntry{
+				name:           prevFlagNameToken.FlagName,
+				value:          token.FlagValue,
+				isInline:       false,
+				isDoubleDashed: prevFlagNameToken.Arg[1] == '-',
+				isBool:         false,
+			}, EntrySpan{
+				Index:       prevFlagNameToken.Index,
+				TokensCount: 2,
+				FlagName:    getFlagNameSpan(prevFlagNameToken),
+				FlagValue:   Span{Index: token.Index},
+			})
+			prevFlagNameToken = Token{}
+			return yieldRes
+		case token.Role.Has(RoleTerminator):
+			return yield(NewTerminatorEntry(), EntrySpan{Index: token.Index, TokensCount: 1})
+		default:
+			if len(unnamedArgs) == 0 {
+				unnamedArgsIndex = token.Index
+			}
+			unnamedArgs = append(unnamedArgs, token.Arg)
+			return true
+		}
+	})
+	if len(unnamedArgs) > 0 {
+		yield(unnamedArgs, EntrySpan{Index: unnamedArgsIndex, TokensCount: len(unnamedArgs)})
+	}
+}
+
+// RenderSpan returns args (quoted as in ShellString()) followed by a new line and "^" characters pointing
+// at the span, like compilers point at errors:
+//
+//	-name x --count=abc
+//	                ^^^
+//
+// If the arg is quoted, the whole arg is pointed. If span.Index == len(args.Args), the position after
+// the last arg is pointed
+func (args Args) RenderSpan(span Span) string {
+	var line strings.Builder
+	caretStart, caretLen := -1, 1
+	for i, arg := range args.Args {
+		if i > 0 {
+			line.WriteByte(' ')
+		}
+		quoted := shellQuote(arg)
+		if i == span.Index {
+			caretStart = utf8.RuneCountInString(line.String())
+			caretLen = utf8.RuneCountInString(quoted)
+			if quoted == arg && span.Start < span.End && span.End <= len(arg) {
+				caretStart += utf8.RuneCountInString(arg[:span.Start])
+				caretLen = utf8.RuneCountInString(arg[span.Start:span.End])
+			}
+		}
+		line.WriteString(quoted)
+	}
+	if caretStart == -1 {
+		caretStart = utf8.RuneCountInString(line.String())
+		if caretStart > 0 {
+			caretStart++
+		}
+	}
+	if caretLen == 0 {
+		caretLen = 1
+	}
+	return line.String() + "\n" + strings.Repeat(" ", caretStart) + strings.Repeat("^", caretLen)
+}
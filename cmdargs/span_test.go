@@ -0,0 +1,45 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs_IterateEntrySpans(t *testing.T) {
+	args := NewArgs([]string{"-s", "some", "--unk=v", "-b", "--b2=true", "x", "y"}).
+		WithKnownFlags(stdutil.FormalTagNames{"s": false, "b": true, "b2": true})
+	var spans []EntrySpan
+	args.IterateEntrySpans(func(entry Entry, span EntrySpan) bool {
+		require.Equal(t, entry.TokensCount(), span.TokensCount)
+		spans = append(spans, span)
+		return true
+	})
+	require.Equal(t, []EntrySpan{
+		{Index: 0, TokensCount: 2, FlagName: Span{0, 1, 2}, FlagValue: Span{Index: 1}},
+		{Index: 2, TokensCount: 1, FlagName: Span{2, 2, 5}, FlagValue: Span{2, 6, 7}},
+		{Index: 3, TokensCount: 1, FlagName: Span{3, 1, 2}},
+		{Index: 4, TokensCount: 1, FlagName: Span{4, 2, 4}, FlagValue: Span{4, 5, 9}},
+		{Index: 5, TokensCount: 2},
+	}, spans)
+}
+
+func TestArgs_RenderSpan(t *testing.T) {
+	args := NewArgs([]string{"-name", "x ü", "--count=abc", "-n=1"})
+	testCases := []struct {
+		span     Span
+		expected string
+	}{
+		{Span{Index: 2, Start: 8, End: 11}, "-name 'x ü' --count=abc -n=1\n                    ^^^"},
+		{Span{Index: 2, Start: 2, End: 7}, "-name 'x ü' --count=abc -n=1\n              ^^^^^"},
+		{Span{Index: 0}, "-name 'x ü' --count=abc -n=1\n^^^^^"},
+		{Span{Index: 1, Start: 0, End: 1}, "-name 'x ü' --count=abc -n=1\n      ^^^^^"},
+		{Span{Index: 3, Start: 3, End: 4}, "-name 'x ü' --count=abc -n=1\n                           ^"},
+		{Span{Index: 4}, "-name 'x ü' --count=abc -n=1\n                             ^"},
+	}
+	for _, testCase := range testCases {
+		require.Equal(t, testCase.expected, args.RenderSpan(testCase.span))
+	}
+	require.Equal(t, "\n^", NewArgs(nil).RenderSpan(Span{}))
+}
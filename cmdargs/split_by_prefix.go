@@ -0,0 +1,59 @@
+package cmdargs
+
+import "strings"
+
+// SplitByPrefix moves flags with names starting with the given prefixes into separate Args. Each element
+// of `split` corresponds to the prefix with the same index and contains flags with the longest matching prefix.
+// `rest` contains other flags, unnamed args and "--" terminator. Flags with names equal to a prefix are
+// not moved. The order of args and the style of flags are preserved.
+// Use TrimFlagsPrefix() to remove the prefix from the names of the split flags
+func (args Args) SplitByPrefix(prefixes ...string) (rest Args, split []Args) {
+	rest.knownFlags = args.knownFlags
+	rest.ambiguousAsBool = args.ambiguousAsBool
+	split = make([]Args, len(prefixes))
+	for i, prefix := range prefixes {
+		split[i].ambiguousAsBool = args.ambiguousAsBool
+		for flagName, isBoolFlag := range args.knownFlags {
+			if hasFlagNamePrefix(flagName, prefix) {
+				if split[i].knownFlags == nil {
+					split[i].knownFlags = make(map[string]bool)
+				}
+				split[i].knownFlags[flagName] = isBoolFlag
+			}
+		}
+	}
+
+	args.IterateEntries(func(entry Entry) bool {
+		prefixIndex := -1
+		if f, isFlag := entry.(FlagEntry); isFlag {
+			for i, prefix := range prefixes {
+				if hasFlagNamePrefix(f.Name(), prefix) &&
+					(prefixIndex == -1 || len(prefix) > len(prefixes[prefixIndex])) {
+					prefixIndex = i
+				}
+			}
+		}
+		if prefixIndex == -1 {
+			rest.Args = append(rest.Args, entry.TokenStrings()...)
+		} else {
+			split[prefixIndex].Args = append(split[prefixIndex].Args, entry.TokenStrings()...)
+		}
+		return true
+	})
+	return rest, split
+}
+
+// TrimFlagsPrefix returns Args where the prefix is removed from the names of the flags starting with it.
+// Flags with names equal to the prefix are left as is
+func (args Args) TrimFlagsPrefix(prefix string) Args {
+	return args.MapFlags(func(flag FlagEntry) Entry {
+		if hasFlagNamePrefix(flag.Name(), prefix) {
+			return flag.WithName(strings.TrimPrefix(flag.Name(), prefix))
+		}
+		return flag
+	})
+}
+
+func hasFlagNamePrefix(flagName string, prefix string) bool {
+	return len(flagName) > len(prefix) && strings.HasPrefix(flagName, prefix)
+}
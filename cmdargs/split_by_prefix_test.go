@@ -0,0 +1,30 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs_SplitByPrefix(t *testing.T) {
+	args := NewArgs([]string{
+		"-v", "--docker-network=host", "-docker-rm", "-docker-", "x", "-docker-compose-file", "a.yml",
+		"--lint-fix", "-name", "n", "--", "--docker-x",
+	}).WithKnownFlags(stdutil.FormalTagNames{"v": true, "docker-rm": true, "lint-fix": true})
+
+	rest, split := args.SplitByPrefix("docker-", "docker-compose-", "lint-", "unused-")
+	require.Equal(t, []string{"-v", "-docker-", "x", "-name", "n", "--", "--docker-x"}, rest.Args)
+	require.Len(t, split, 4)
+	require.Equal(t, []string{"--docker-network=host", "-docker-rm"}, split[0].Args)
+	require.Equal(t, []string{"-docker-compose-file", "a.yml"}, split[1].Args)
+	require.Equal(t, []string{"--lint-fix"}, split[2].Args)
+	require.Nil(t, split[3].Args)
+
+	require.Equal(t, []string{"--network=host", "-rm"}, split[0].TrimFlagsPrefix("docker-").Args)
+	require.Equal(t, []string{"--fix"}, split[2].TrimFlagsPrefix("lint-").Args)
+
+	flagEntry, has := split[0].TrimFlagsPrefix("docker-").LookupFlag("rm")
+	require.True(t, has)
+	require.True(t, flagEntry.IsBool())
+}
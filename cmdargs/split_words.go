@@ -0,0 +1,110 @@
+package cmdargs
+
+import (
+	"strings"
+)
+
+// word is a part of a text split by splitWords()
+type word struct {
+	value string
+	// line is a 1-based number of the line where the word starts
+	line int
+}
+
+// splitWordsError describes invalid syntax of a text split by splitWords()
+type splitWordsError struct {
+	line    int
+	message string
+}
+
+// splitWords splits a text into words separated by whitespace (including new lines) following
+// POSIX shell quoting rules:
+// - single-quoted parts are taken literally
+// - backslash escapes `"`, `\`, `$`, "`" and new line inside double quotes
+// - backslash escapes any character outside of quotes
+// - escaped new line is removed (line continuation)
+func splitWords(text string) ([]word, *splitWordsError) {
+	var res []word
+	var sb strings.Builder
+	hasWord := false
+	wordLine := 0
+	line := 1
+	runes := []rune(text)
+
+	startWord := func() {
+		if !hasWord {
+			hasWord = true
+			wordLine = line
+		}
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n' || r == ' ' || r == '\t' || r == '\r':
+			if hasWord {
+				res = append(res, word{value: sb.String(), line: wordLine})
+				sb.Reset()
+				hasWord = false
+			}
+			if r == '\n' {
+				line++
+			}
+		case r == '\\':
+			if i+1 == len(runes) {
+				startWord()
+				sb.WriteRune(r)
+				break
+			}
+			i++
+			if runes[i] == '\n' {
+				line++
+				break
+			}
+			startWord()
+			sb.WriteRune(runes[i])
+		case r == '\'':
+			startWord()
+			quoteLine := line
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '\''; end++ {
+				if runes[end] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[end])
+			}
+			if end == len(runes) {
+				return nil, &splitWordsError{line: quoteLine, message: "unterminated single quote"}
+			}
+			i = end
+		case r == '"':
+			startWord()
+			quoteLine := line
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				c := runes[end]
+				if c == '\\' && end+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[end+1]) {
+					end++
+					c = runes[end]
+					if c == '\n' {
+						line++
+						continue
+					}
+				} else if c == '\n' {
+					line++
+				}
+				sb.WriteRune(c)
+			}
+			if end == len(runes) {
+				return nil, &splitWordsError{line: quoteLine, message: "unterminated double quote"}
+			}
+			i = end
+		default:
+			startWord()
+			sb.WriteRune(r)
+		}
+	}
+	if hasWord {
+		res = append(res, word{value: sb.String(), line: wordLine})
+	}
+	return res, nil
+}
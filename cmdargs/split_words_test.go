@@ -0,0 +1,41 @@
+package cmdargs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitWords(t *testing.T) {
+	testCases := []struct {
+		name  string
+		text  string
+		words []word
+	}{
+		{"empty", " \n\t ", nil},
+		{"plain", "a  bc\n-d=e", []word{{"a", 1}, {"bc", 1}, {"-d=e", 2}}},
+		{"single quotes", `'a b' 'c"\d'e ''`, []word{{"a b", 1}, {`c"\de`, 1}, {"", 1}}},
+		{
+			"double quotes",
+			`"a b" "c\"\\\$\d" "x` + "\ny\"",
+			[]word{{"a b", 1}, {`c"\$\d`, 1}, {"x\ny", 1}},
+		},
+		{"escapes", `a\ b \'c\" d\`, []word{{"a b", 1}, {`'c"`, 1}, {`d\`, 1}}},
+		{"line continuation", "a\\\nb \"c\\\nd\"\ne", []word{{"ab", 1}, {"cd", 2}, {"e", 4}}},
+		{"multiline quote", "'a\nb' c", []word{{"a\nb", 1}, {"c", 2}}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			words, err := splitWords(testCase.text)
+			require.Nil(t, err)
+			require.Equal(t, testCase.words, words)
+		})
+	}
+}
+
+func TestSplitWordsErrors(t *testing.T) {
+	_, err := splitWords("a\nb 'c\nd")
+	require.Equal(t, &splitWordsError{line: 2, message: "unterminated single quote"}, err)
+	_, err = splitWords("a\n\"b\\\"")
+	require.Equal(t, &splitWordsError{line: 2, message: "unterminated double quote"}, err)
+}
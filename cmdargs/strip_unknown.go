@@ -4,27 +4,94 @@ import (
 	"github.com/cardinalby/go-struct-flags/stdutil"
 )
 
+// StripUnknownFlags splits args into args containing known flags (and unnamed args) and args containing
+// unknown flags with their values.
+// `ignoredFlagsWithKnownType` describes types of flags that are not known but should be classified precisely
+// (e.g. flags of a downstream tool), other ambiguous unknown flags are treated according to WithAmbiguousAsBool()
 func (args Args) StripUnknownFlags(
 	ignoredFlagsWithKnownType stdutil.FormalTagNames,
 ) (res, stripped Args) {
-	c := args.WithKnownFlags(ignoredFlagsWithKnownType)
-	res.knownFlags = args.knownFlags
-	res.ambiguousAsBool = args.ambiguousAsBool
+	res, _, _, strippedSpans := args.stripUnknownFlagSpans(ignoredFlagsWithKnownType)
 	stripped.knownFlags = args.knownFlags
 	stripped.ambiguousAsBool = args.ambiguousAsBool
+	for _, span := range strippedSpans {
+		stripped.Args = append(stripped.Args, args.getEntryArgs(span)...)
+	}
+	return res, stripped
+}
+
+// StripUnknownFlagEntries is the same as StripUnknownFlags but returns the stripped flags as FlagEntry
+// objects (typed as Entry) preserving their names, values and formatting.
+// Prefixes and normalized names (see WithPrefixMatching, WithNameNormalizer) are resolved only to the known
+// flags, not to `ignoredFlagsWithKnownType`, so the stripped flags keep the passed names
+func (args Args) StripUnknownFlagEntries(
+	ignoredFlagsWithKnownType stdutil.FormalTagNames,
+) (res Args, stripped []Entry) {
+	res, _, stripped, _ = args.stripUnknownFlagSpans(ignoredFlagsWithKnownType)
+	return res, stripped
+}
+
+// StripUnknownFlagEntriesWithIndexes is the same as StripUnknownFlagEntries but also returns `argIndexes`:
+// argIndexes[i] is the index of res.Args[i] in args.Args
+func (args Args) StripUnknownFlagEntriesWithIndexes(
+	ignoredFlagsWithKnownType stdutil.FormalTagNames,
+) (res Args, argIndexes []int, stripped []Entry) {
+	res, argIndexes, stripped, _ = args.stripUnknownFlagSpans(ignoredFlagsWithKnownType)
+	return res, argIndexes, stripped
+}
+
+// stripUnknownFlagSpans implements StripUnknownFlagEntries also returning the positions of the stripped
+// entries in args.Args. `res` contains the args of the kept entries as they are passed
+func (args Args) stripUnknownFlagSpans(
+	ignoredFlagsWithKnownType stdutil.FormalTagNames,
+) (res Args, argIndexes []int, stripped []Entry, strippedSpans []EntrySpan) {
+	// types of the known flags take precedence over ignoredFlagsWithKnownType
+	c := args.WithKnownFlags(ignoredFlagsWithKnownType).WithKnownFlags(args.knownFlags)
+	c.unresolvableFlags = make(stdutil.FormalTagNames)
+	for flagName, isBoolFlag := range ignoredFlagsWithKnownType {
+		if _, isKnown := args.knownFlags[flagName]; !isKnown {
+			c.unresolvableFlags[flagName] = isBoolFlag
+		}
+	}
+	res.knownFlags = args.knownFlags
+	res.ambiguousAsBool = args.ambiguousAsBool
+	argIndexes = make([]int, 0, len(args.Args))
 
 	isKnownFlag := func(flagName string) bool {
 		_, has := args.knownFlags[flagName]
 		return has
 	}
-	c.IterateEntries(func(entry Entry) bool {
+	end := 0
+	c.IterateEntrySpans(func(entry Entry, span EntrySpan) bool {
+		if span.Index+span.TokensCount > end {
+			end = span.Index + span.TokensCount
+		}
 		if f, isFlag := entry.(FlagEntry); isFlag && !isKnownFlag(f.Name()) {
-			stripped.Args = append(stripped.Args, entry.TokenStrings()...)
+			stripped = append(stripped, entry)
+			strippedSpans = append(strippedSpans, span)
 		} else {
-			res.Args = append(res.Args, entry.TokenStrings()...)
+			res.Args = append(res.Args, args.getEntryArgs(span)...)
+			for i := 0; i < span.TokensCount; i++ {
+				argIndexes = append(argIndexes, span.Index+i)
+			}
 		}
 		return true
 	})
+	if end < len(args.Args) {
+		// a known flag without a value at the end is not an entry. Keep it to let the parser report it
+		c.IterateTokens(func(token Token) bool {
+			if token.Index >= end && token.Role.Has(RoleFlag) && isKnownFlag(token.FlagName) {
+				res.Args = append(res.Args, token.Arg)
+				argIndexes = append(argIndexes, token.Index)
+			}
+			return true
+		})
+	}
 
-	return res, stripped
+	return res, argIndexes, stripped, strippedSpans
+}
+
+// getEntryArgs returns the args of the entry at `span` as they are passed
+func (args Args) getEntryArgs(span EntrySpan) []string {
+	return args.Args[span.Index : span.Index+span.TokensCount]
 }
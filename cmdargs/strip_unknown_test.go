@@ -243,3 +243,58 @@ func TestStripUnknownFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestStripUnknownFlagEntries(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.String("s", "", "")
+	res, stripped := NewArgs([]string{"-s", "a", "--x=1", "-v", "-y", "2", "rem"}).
+		WithFlagSet(fs).
+		StripUnknownFlagEntries(stdutil.FormalTagNames{"v": true})
+	require.Equal(t, []string{"-s", "a", "rem"}, res.Args)
+	require.Equal(t, []Entry{
+		NewFlagEntry("x", "1").WithInline(true).WithDoubleDashes(true),
+		NewBoolFlagEntry("v", ""),
+		NewFlagEntry("y", "2"),
+	}, stripped)
+}
+
+func TestStripUnknownFlags_KnownFlagsPrecedence(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.String("s", "", "")
+	res, stripped := NewArgs([]string{"-s", "-v", "-v", "-o", "out"}).
+		WithFlagSet(fs).
+		StripUnknownFlags(stdutil.FormalTagNames{"s": true, "v": true, "o": false})
+	require.Equal(t, []string{"-s", "-v"}, res.Args)
+	require.Equal(t, []string{"-v", "-o", "out"}, stripped.Args)
+}
+
+func TestStripUnknownFlags_PrefixMatching(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.Bool("verbose", false, "")
+	res, stripped := NewArgs([]string{"--net", "host", "--verb", "--Network=x"}).
+		WithFlagSet(fs).
+		WithPrefixMatching(true).
+		WithNameNormalizer(FoldCaseAndSeparators).
+		StripUnknownFlags(stdutil.FormalTagNames{"network": false})
+	// names are resolved only to the known flags, args keep the passed text
+	require.Equal(t, []string{"--verb"}, res.Args)
+	require.Equal(t, []string{"--net", "host", "--Network=x"}, stripped.Args)
+}
+
+func TestStripUnknownFlagEntriesWithIndexes(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.Int("n", 0, "")
+	res, argIndexes, stripped := NewArgs([]string{"-u=1", "-n", "2", "-v", "x", "pos"}).
+		WithFlagSet(fs).
+		StripUnknownFlagEntriesWithIndexes(stdutil.FormalTagNames{"v": false})
+	require.Equal(t, []string{"-n", "2", "pos"}, res.Args)
+	require.Equal(t, []int{1, 2, 5}, argIndexes)
+	require.Len(t, stripped, 2)
+
+	// a known flag without a value is kept
+	res, argIndexes, _ = NewArgs([]string{"-u=1", "-n"}).
+		WithFlagSet(fs).
+		StripUnknownFlagEntriesWithIndexes(nil)
+	require.Equal(t, []string{"-n"}, res.Args)
+	require.Equal(t, []int{1}, argIndexes)
+}
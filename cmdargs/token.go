@@ -17,9 +17,21 @@ const (
 )
 
 type Token struct {
-	Arg       string
-	FlagName  string
-	FlagValue string
+	Arg string
+	// Index is the index of Arg in Args.Args
+	Index int
+	// FlagName is the known flag name (even if it's passed as a prefix or in a different form,
+	// see PassedFlagName)
+	FlagName string
+	// PassedFlagName is the flag name as it's passed in Arg if it's resolved to a different known FlagName
+	// (see Args.WithPrefixMatching, Args.WithNameNormalizer). Empty if Arg contains FlagName
+	PassedFlagName string
+	// FlagNameOffset is the byte offset of FlagName (or PassedFlagName) in Arg (after dashes).
+	// 0 if the token is not a flag
+	FlagNameOffset int
+	FlagValue      string
+	// FlagValueOffset is the byte offset of FlagValue in Arg for inline flags (after "="). 0 otherwise
+	FlagValueOffset int
 	// Role is sum of Role constants. Possible values:
 	// RoleFlag | RoleKnown | RoleInline                 // contains FlagValue
 	// RoleFlag | RoleKnown | RoleBoolFlag               // no FlagValue, implicit `true`
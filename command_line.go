@@ -3,6 +3,9 @@ package flago
 import (
 	"flag"
 	"os"
+
+	"github.com/cardinalby/go-struct-flags/cmdargs"
+	"github.com/cardinalby/go-struct-flags/stdutil"
 )
 
 // CommandLine is a default FlagSet that is used by the package functions.
@@ -36,6 +39,12 @@ func StructVarWithPrefix(p any, flagsPrefix string, ignoredFields ...any) error
 	return CommandLine.StructVarWithPrefix(p, flagsPrefix, ignoredFields...)
 }
 
+// StructVarOpts registers the given struct with the default FlagSet customizing the registration with `opts`.
+// See FlagSet.StructVarOpts
+func StructVarOpts(p any, opts ...Option) error {
+	return CommandLine.StructVarOpts(p, opts...)
+}
+
 // SetAllowParsingMultipleAliases sets the behavior of Parse() when multiple tag names
 // assigned to same field are passed.
 // If `true`, it will be ignored and only the last value will be used.
@@ -45,6 +54,24 @@ func SetAllowParsingMultipleAliases(allow bool) {
 	CommandLine.SetAllowParsingMultipleAliases(allow)
 }
 
+// SetPrefixMatching sets the behavior of Parse() when an unknown flag name is passed.
+// See FlagSet.SetPrefixMatching
+func SetPrefixMatching(enabled bool) {
+	CommandLine.SetPrefixMatching(enabled)
+}
+
+// SetNameNormalizer sets the behavior of Parse() when an unknown flag name is passed.
+// See FlagSet.SetNameNormalizer
+func SetNameNormalizer(normalizer cmdargs.NameNormalizer) {
+	CommandLine.SetNameNormalizer(normalizer)
+}
+
+// SetNamingStrategy sets the behavior of StructVar() for fields without tags.
+// See FlagSet.SetNamingStrategy
+func SetNamingStrategy(strategy NamingStrategy) {
+	CommandLine.SetNamingStrategy(strategy)
+}
+
 // SetIgnoreUnknown sets the behavior of Parse() when unknown flags are passed.
 // If `true`, they will be ignored.
 // If `false`, Parse() will return an error.
@@ -62,12 +89,42 @@ func SetIgnoreUnknownAmbiguousAsBoolFlags(treatAsBool bool) {
 	CommandLine.SetIgnoreUnknownAmbiguousAsBoolFlags(treatAsBool)
 }
 
+// SetResponseFileExpansion sets the behavior of Parse() when `@path` args are passed.
+// See FlagSet.SetResponseFileExpansion
+func SetResponseFileExpansion(enabled bool) {
+	CommandLine.SetResponseFileExpansion(enabled)
+}
+
+// SetUnknownFlagSuggestions sets the behavior of Parse() when an unknown flag is passed.
+// See FlagSet.SetUnknownFlagSuggestions
+func SetUnknownFlagSuggestions(enabled bool) {
+	CommandLine.SetUnknownFlagSuggestions(enabled)
+}
+
+// SetUnknownFlagsManifest specifies known types of flags that are expected to be unknown.
+// See FlagSet.SetUnknownFlagsManifest
+func SetUnknownFlagsManifest(manifest stdutil.FormalTagNames) {
+	CommandLine.SetUnknownFlagsManifest(manifest)
+}
+
+// SetUsageWidth sets the width of the help message printed by PrintDefaults().
+// See FlagSet.SetUsageWidth
+func SetUsageWidth(width int) {
+	CommandLine.SetUsageWidth(width)
+}
+
 // GetIgnoredArgs returns a slice of arguments that were ignored during the last call to Parse()
 // because of SetIgnoreUnknown(true), nil otherwise
 func GetIgnoredArgs() []string {
 	return CommandLine.GetIgnoredArgs()
 }
 
+// GetIgnoredEntries returns flags that were ignored during the last call to Parse()
+// because of SetIgnoreUnknown(true), nil otherwise
+func GetIgnoredEntries() []cmdargs.Entry {
+	return CommandLine.GetIgnoredEntries()
+}
+
 // Parse parses the command-line flags using the default FlagSet
 func Parse() error {
 	return CommandLine.Parse(os.Args[1:])
@@ -0,0 +1,387 @@
+package flago
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DotEnvSyntaxError is returned if a `.env` file has invalid syntax
+type DotEnvSyntaxError struct {
+	FileName string
+	Line     int
+	Message  string
+}
+
+func (e *DotEnvSyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.FileName, e.Line, e.Message)
+}
+
+// DotEnvValueError is returned by Parse() if a value provided by AddDotEnvFile source is invalid.
+// Err is *InvalidValueError
+type DotEnvValueError struct {
+	FileName string
+	// Line is the line where the variable is defined
+	Line int
+	Err  error
+}
+
+func (e *DotEnvValueError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.FileName, e.Line, e.Err)
+}
+
+func (e *DotEnvValueError) Unwrap() error {
+	return e.Err
+}
+
+// ReadDotEnvFile reads variables from the `.env` file. Supported syntax:
+// - `NAME=value` lines, optionally prefixed with `export `
+// - comments: lines starting with `#` and ` #...` at the end of unquoted values
+// - single-quoted values are taken literally
+// - double-quoted values can contain escape sequences (\n, \t, \", \\, \$) and span multiple lines
+// - unquoted and double-quoted values can reference variables defined above in the file or environment
+// variables: $NAME, ${NAME}, ${NAME:-default}
+func ReadDotEnvFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDotEnv(string(content), path, os.LookupEnv)
+}
+
+func readDotEnvFileVars(path string) (map[string]dotEnvVar, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDotEnvVars(string(content), path, os.LookupEnv)
+}
+
+// AddDotEnvFile reads variables from the `.env` file (see ReadDotEnvFile) and adds a Source (see AddSource)
+// providing their values for the flags.
+// A variable name for a flag is specified by `flagEnv` tag of the field. If there is no such tag,
+// the name is derived from the primary flag name: it's converted to upper case, characters other than
+// letters and digits are replaced with "_" and `envPrefix` is added.
+// Invalid values cause Parse() to return *DotEnvValueError pointing to the line of the variable
+func (fls *FlagSet) AddDotEnvFile(path string, envPrefix string, priority int) error {
+	vars, err := readDotEnvFileVars(path)
+	if err != nil {
+		return err
+	}
+	fls.AddSource(&dotEnvSource{
+		fileName: path,
+		vars:     vars,
+		getVarName: func(flagName string) string {
+			return fls.getFlagEnvVarName(envPrefix, flagName)
+		},
+	}, priority)
+	return nil
+}
+
+type dotEnvSource struct {
+	fileName   string
+	vars       map[string]dotEnvVar
+	getVarName func(flagName string) string
+}
+
+func (s *dotEnvSource) Lookup(flagName string, _ string) (string, bool, error) {
+	v, ok := s.vars[s.getVarName(flagName)]
+	return v.value, ok, nil
+}
+
+func (s *dotEnvSource) wrapValueError(flagName string, err error) error {
+	return &DotEnvValueError{
+		FileName: s.fileName,
+		Line:     s.vars[s.getVarName(flagName)].line,
+		Err:      err,
+	}
+}
+
+// getFlagEnvVarName returns the name specified in `flagEnv` tag of the field or derives it
+// from the flag name
+func (fls *FlagSet) getFlagEnvVarName(envPrefix string, flagName string) string {
+	if envVarName, ok := fls.envVarNames[flagName]; ok {
+		return envVarName
+	}
+	return getEnvVarName(envPrefix, flagName)
+}
+
+type dotEnvVar struct {
+	value string
+	// line is the line where the variable is defined
+	line int
+}
+
+type dotEnvParser struct {
+	content   []rune
+	pos       int
+	line      int
+	fileName  string
+	vars      map[string]dotEnvVar
+	lookupEnv func(name string) (string, bool)
+}
+
+func parseDotEnv(
+	content string,
+	fileName string,
+	lookupEnv func(name string) (string, bool),
+) (map[string]string, error) {
+	vars, err := parseDotEnvVars(content, fileName, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(vars))
+	for name, v := range vars {
+		res[name] = v.value
+	}
+	return res, nil
+}
+
+func parseDotEnvVars(
+	content string,
+	fileName string,
+	lookupEnv func(name string) (string, bool),
+) (map[string]dotEnvVar, error) {
+	p := dotEnvParser{
+		content:   []rune(content),
+		line:      1,
+		fileName:  fileName,
+		vars:      make(map[string]dotEnvVar),
+		lookupEnv: lookupEnv,
+	}
+	for !p.isEOF() {
+		if err := p.parseLine(); err != nil {
+			return nil, err
+		}
+	}
+	return p.vars, nil
+}
+
+func (p *dotEnvParser) isEOF() bool {
+	return p.pos >= len(p.content)
+}
+
+func (p *dotEnvParser) peek() rune {
+	return p.content[p.pos]
+}
+
+func (p *dotEnvParser) next() rune {
+	r := p.content[p.pos]
+	p.pos++
+	if r == '\n' {
+		p.line++
+	}
+	return r
+}
+
+func (p *dotEnvParser) errorf(format string, a ...any) error {
+	return &DotEnvSyntaxError{
+		FileName: p.fileName,
+		Line:     p.line,
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
+
+func (p *dotEnvParser) skipSpaces() {
+	for !p.isEOF() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r') {
+		p.next()
+	}
+}
+
+// skipLineRest skips spaces and a comment till the end of the line returning an error
+// if there are other characters
+func (p *dotEnvParser) skipLineRest() error {
+	p.skipSpaces()
+	if !p.isEOF() && p.peek() == '#' {
+		for !p.isEOF() && p.peek() != '\n' {
+			p.next()
+		}
+	}
+	if p.isEOF() {
+		return nil
+	}
+	if p.peek() != '\n' {
+		return p.errorf("unexpected character %q", p.peek())
+	}
+	p.next()
+	return nil
+}
+
+func (p *dotEnvParser) parseLine() error {
+	p.skipSpaces()
+	if p.isEOF() || p.peek() == '#' || p.peek() == '\n' {
+		return p.skipLineRest()
+	}
+	line := p.line
+	name := p.parseName()
+	if name == "export" && !p.isEOF() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipSpaces()
+		name = p.parseName()
+	}
+	if name == "" {
+		return p.errorf("expected variable name")
+	}
+	p.skipSpaces()
+	if p.isEOF() || p.peek() != '=' {
+		return p.errorf(`expected "=" after variable name "%s"`, name)
+	}
+	p.next()
+	p.skipSpaces()
+
+	var value string
+	var err error
+	switch {
+	case p.isEOF():
+	case p.peek() == '\'':
+		value, err = p.parseSingleQuoted()
+	case p.peek() == '"':
+		value, err = p.parseDoubleQuoted()
+	default:
+		value, err = p.parseUnquoted()
+	}
+	if err != nil {
+		return err
+	}
+	p.vars[name] = dotEnvVar{value: value, line: line}
+	return p.skipLineRest()
+}
+
+func (p *dotEnvParser) parseName() string {
+	start := p.pos
+	for !p.isEOF() && isDotEnvNameChar(p.peek(), p.pos == start) {
+		p.next()
+	}
+	return string(p.content[start:p.pos])
+}
+
+func isDotEnvNameChar(r rune, isFirst bool) bool {
+	return r == '_' ||
+		(r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') ||
+		(!isFirst && (r == '.' || (r >= '0' && r <= '9')))
+}
+
+func (p *dotEnvParser) parseSingleQuoted() (string, error) {
+	startLine := p.line
+	p.next()
+	sb := strings.Builder{}
+	for !p.isEOF() {
+		if r := p.next(); r == '\'' {
+			return sb.String(), nil
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	p.line = startLine
+	return "", p.errorf("unterminated single-quoted value")
+}
+
+func (p *dotEnvParser) parseDoubleQuoted() (string, error) {
+	startLine := p.line
+	p.next()
+	sb := strings.Builder{}
+	for !p.isEOF() {
+		r := p.next()
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.isEOF() {
+				break
+			}
+			switch escaped := p.next(); escaped {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '"', '\\', '$':
+				sb.WriteRune(escaped)
+			default:
+				sb.WriteRune('\\')
+				sb.WriteRune(escaped)
+			}
+		case '$':
+			if err := p.parseVarReference(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	p.line = startLine
+	return "", p.errorf("unterminated double-quoted value")
+}
+
+func (p *dotEnvParser) parseUnquoted() (string, error) {
+	sb := strings.Builder{}
+	for !p.isEOF() && p.peek() != '\n' {
+		r := p.peek()
+		if r == '#' && p.pos > 0 && (p.content[p.pos-1] == ' ' || p.content[p.pos-1] == '\t') {
+			break
+		}
+		p.next()
+		if r == '$' {
+			if err := p.parseVarReference(&sb); err != nil {
+				return "", err
+			}
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimRight(sb.String(), " \t\r"), nil
+}
+
+// parseVarReference parses a reference following "$" and writes the referenced value to `sb`
+func (p *dotEnvParser) parseVarReference(sb *strings.Builder) error {
+	if p.isEOF() {
+		sb.WriteRune('$')
+		return nil
+	}
+	if p.peek() != '{' {
+		name := p.parseName()
+		if name == "" {
+			sb.WriteRune('$')
+		} else {
+			sb.WriteString(p.getVar(name))
+		}
+		return nil
+	}
+	p.next()
+	name := p.parseName()
+	if name == "" {
+		return p.errorf("expected variable name after \"${\"")
+	}
+	var defaultValue *string
+	if p.pos+1 < len(p.content) && p.peek() == ':' && p.content[p.pos+1] == '-' {
+		p.next()
+		p.next()
+		defaultValueSb := strings.Builder{}
+		for !p.isEOF() && p.peek() != '}' && p.peek() != '\n' {
+			defaultValueSb.WriteRune(p.next())
+		}
+		defaultValueStr := defaultValueSb.String()
+		defaultValue = &defaultValueStr
+	}
+	if p.isEOF() || p.peek() != '}' {
+		return p.errorf(`expected "}" after variable name "%s"`, name)
+	}
+	p.next()
+	if value := p.getVar(name); value != "" || defaultValue == nil {
+		sb.WriteString(value)
+	} else {
+		sb.WriteString(*defaultValue)
+	}
+	return nil
+}
+
+func (p *dotEnvParser) getVar(name string) string {
+	if v, ok := p.vars[name]; ok {
+		return v.value
+	}
+	if value, ok := p.lookupEnv(name); ok {
+		return value
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package flago
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	env := map[string]string{"HOME": "/home/user"}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	content := `
+# comment
+  A=1
+export B = value with spaces # comment
+C='single $A # "quoted"'
+D="double\t$A ${B} \$A \"q\" 
+second line"
+E=${UNDEFINED:-default}/${A:-x}
+F=$HOME/dir#not_comment
+G=
+H=""
+i.j_2=$UNDEFINED
+`
+	vars, err := parseDotEnv(content, ".env", lookupEnv)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"A":     "1",
+		"B":     "value with spaces",
+		"C":     `single $A # "quoted"`,
+		"D":     "double\t1 value with spaces $A \"q\" \nsecond line",
+		"E":     "default/1",
+		"F":     "/home/user/dir#not_comment",
+		"G":     "",
+		"H":     "",
+		"i.j_2": "",
+	}, vars)
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	lookupEnv := func(string) (string, bool) { return "", false }
+	testCases := []struct {
+		content string
+		line    int
+		message string
+	}{
+		{"A=1\n\n=2", 3, "expected variable name"},
+		{"A=1\nB", 2, `expected "=" after variable name "B"`},
+		{"A=1\nB='1\n\n", 2, "unterminated single-quoted value"},
+		{"A=\"1\\\"\nB=2", 1, "unterminated double-quoted value"},
+		{"A=\"1\" 2", 1, "unexpected character '2'"},
+		{"A=1\nB=${A", 2, `expected "}" after variable name "A"`},
+		{"A=${}", 1, `expected variable name after "${"`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.content, func(t *testing.T) {
+			_, err := parseDotEnv(testCase.content, "test.env", lookupEnv)
+			var syntaxErr *DotEnvSyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			require.Equal(t, "test.env", syntaxErr.FileName)
+			require.Equal(t, testCase.line, syntaxErr.Line)
+			require.Equal(t, testCase.message, syntaxErr.Message)
+		})
+	}
+}
+
+func TestAddDotEnvFile(t *testing.T) {
+	type nested struct {
+		Email string `flag:"email"`
+	}
+	type testStruct struct {
+		Login  string  `flags:"login,l"`
+		Token  *string `flag:"token" flagEnv:"SECRET_TOKEN"`
+		Port   int     `flag:"port"`
+		Sender nested  `flagPrefix:"sender-"`
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"APP_LOGIN=env_login\nSECRET_TOKEN=abc\nAPP_PORT=80\nAPP_SENDER_EMAIL=a@b.c\n",
+	), 0600))
+
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.AddDotEnvFile(path, "APP_", 0))
+	require.NoError(t, fls.Parse([]string{"-l", "cmd_login"}))
+	require.Equal(t, "cmd_login", structVal.Login)
+	require.Equal(t, "abc", *structVal.Token)
+	require.Equal(t, 80, structVal.Port)
+	require.Equal(t, "a@b.c", structVal.Sender.Email)
+	require.Equal(t, []string{
+		"APP_LOGIN=cmd_login",
+		"APP_PORT=80",
+		"APP_SENDER_EMAIL=a@b.c",
+		"SECRET_TOKEN=abc",
+	}, fls.ToEnv("APP_"))
+
+	require.NoError(t, os.WriteFile(path, []byte("APP_PORT=80\nAPP_LOGIN\n"), 0600))
+	err := fls.AddDotEnvFile(path, "APP_", 0)
+	require.EqualError(t, err, path+`:2: expected "=" after variable name "APP_LOGIN"`)
+
+	require.Error(t, fls.AddDotEnvFile(filepath.Join(t.TempDir(), "missing.env"), "APP_", 0))
+}
+
+func TestAddDotEnvFileInvalidValue(t *testing.T) {
+	type testStruct struct {
+		Login string `flag:"login"`
+		Port  int    `flag:"port"`
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\nAPP_LOGIN=\"a\nb\"\n\nAPP_PORT=abc\n"), 0600))
+
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.SetOutput(io.Discard)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.AddDotEnvFile(path, "APP_", 0))
+	err := fls.Parse(nil)
+	require.EqualError(t, err, path+`:5: invalid value "abc" for flag -port provided by source: parse error`)
+
+	var dotEnvValueErr *DotEnvValueError
+	require.ErrorAs(t, err, &dotEnvValueErr)
+	require.Equal(t, path, dotEnvValueErr.FileName)
+	require.Equal(t, 5, dotEnvValueErr.Line)
+	var invalidValueErr *InvalidValueError
+	require.ErrorAs(t, err, &invalidValueErr)
+	require.Equal(t, "port", invalidValueErr.Flag)
+	require.Equal(t, -1, invalidValueErr.ArgIndex)
+}
@@ -0,0 +1,187 @@
+package flago
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/cardinalby/go-struct-flags/cmdargs"
+)
+
+// effectiveFlagValue describes the current value of a field (or a flag registered directly in the
+// wrapped FlagSet)
+type effectiveFlagValue struct {
+	// flagName is the primary flag name
+	flagName string
+	groups   []flagsGroup
+	flag     *flag.Flag
+	value    string
+	// isDefault indicates that the value is equal to the default value of the flag
+	isDefault bool
+}
+
+// getEffectiveFlagValues returns current values of the registered flags sorted by the flag names.
+// Nil pointer fields and fields of nil struct pointers are skipped
+func (fls *FlagSet) getEffectiveFlagValues() (res []effectiveFlagValue) {
+	for _, field := range fls.getFlagFields() {
+		if field.namedFlagsField != nil && !field.namedFlagsField.info.lazyStruct.isAssigned() {
+			continue
+		}
+		value := effectiveFlagValue{
+			flagName: field.flagNames[0],
+			groups:   field.getGroups(),
+			flag:     field.flag,
+		}
+		if field.namedFlagsField != nil && field.namedFlagsField.isOptional {
+			fieldValue := field.namedFlagsField.info.fieldValue
+			if fieldValue.IsNil() {
+				continue
+			}
+			value.value = fmt.Sprint(getAccessibleValue(fieldValue.Elem()).Interface())
+		} else {
+			value.value = field.flag.Value.String()
+			value.isDefault = value.value == field.flag.DefValue
+		}
+		res = append(res, value)
+	}
+	return res
+}
+
+// ToArgs returns the command line args that reproduce the current values of the registered
+// struct fields (and flags registered in the wrapped FlagSet directly).
+// Flags having default values and nil pointer fields are skipped. Primary names (the first names of
+// not deprecated `flags` aliases) are used. Positional args (values of `flagArgs` field or FlagSet.Args())
+// are added after the flags, separated by "--" if needed.
+// Unlike ToJSON(), values of the fields tagged with `flagSecret` are not redacted to be passed to child processes
+func (fls *FlagSet) ToArgs() []string {
+	var res []string
+	for _, value := range fls.getEffectiveFlagValues() {
+		if value.isDefault {
+			continue
+		}
+		if sv, ok := unwrapFlagValue(value.flag.Value).(*sliceValue); ok {
+			for _, elem := range sv.getStrings() {
+				res = append(res, cmdargs.NewFlagEntry(value.flagName, elem).TokenStrings()...)
+			}
+			continue
+		}
+		var entry cmdargs.FlagEntry
+		if isBoolFlagValue(value.flag.Value) {
+			if value.value == "true" {
+				entry = cmdargs.NewBoolFlagEntry(value.flagName, "")
+			} else {
+				entry = cmdargs.NewBoolFlagEntry(value.flagName, value.value)
+			}
+		} else {
+			entry = cmdargs.NewFlagEntry(value.flagName, value.value)
+		}
+		res = append(res, entry.TokenStrings()...)
+	}
+
+	positionalArgs := fls.getPositionalArgs()
+	if len(positionalArgs) > 0 {
+		if firstArg := positionalArgs[0]; len(firstArg) > 1 && firstArg[0] == '-' {
+			res = append(res, cmdargs.NewTerminatorEntry().TokenStrings()...)
+		}
+		res = append(res, positionalArgs...)
+	}
+	return res
+}
+
+// ToJSON returns JSON object containing the current values of all registered struct fields
+// (and flags registered in the wrapped FlagSet directly) except nil pointer fields.
+// The object has the same structure as described by WriteJSONSchema(). Positional args are not included.
+// Values of the fields tagged with `flagSecret` are replaced with cmdargs.RedactedValue, hidden flags
+// are skipped as in WriteJSONSchema().
+// Returns an error if a flag and a nested struct have the same key (see WriteJSONSchema)
+func (fls *FlagSet) ToJSON() ([]byte, error) {
+	root := make(map[string]any)
+	// groupFlagPrefixes contains flag prefixes of the added groups by their paths of keys
+	groupFlagPrefixes := make(map[string]string)
+	for _, value := range fls.getEffectiveFlagValues() {
+		if _, isHidden := fls.hiddenFlagNames[value.flagName]; isHidden {
+			continue
+		}
+		parent := root
+		key := value.flagName
+		path := ""
+		for _, group := range value.groups {
+			path += "\x00" + group.key
+			existing, exists := parent[group.key]
+			groupObject, isGroup := existing.(map[string]any)
+			if exists && !isGroup {
+				return nil, newJSONKeyCollisionError(group.key, group.flagPrefix)
+			}
+			if !exists {
+				groupObject = make(map[string]any)
+				parent[group.key] = groupObject
+				groupFlagPrefixes[path] = group.flagPrefix
+			}
+			parent = groupObject
+		}
+		if len(value.groups) > 0 {
+			key = strings.TrimPrefix(key, value.groups[len(value.groups)-1].flagPrefix)
+		}
+		if flagPrefix, isGroup := groupFlagPrefixes[path+"\x00"+key]; isGroup {
+			return nil, newJSONKeyCollisionError(key, flagPrefix)
+		}
+		if fls.isSecretFlag(value.flagName) {
+			parent[key] = cmdargs.RedactedValue
+		} else if sv, ok := unwrapFlagValue(value.flag.Value).(*sliceValue); ok {
+			jsonType, _ := getFlagValueJSONType(sv.elemFlag.Value)
+			elems := make([]any, 0, sv.slice.Len())
+			for _, elem := range sv.getStrings() {
+				elems = append(elems, getJSONSchemaValue(jsonType, elem))
+			}
+			parent[key] = elems
+		} else {
+			jsonType, _ := getFlagValueJSONType(value.flag.Value)
+			parent[key] = getJSONSchemaValue(jsonType, value.value)
+		}
+	}
+	return json.Marshal(root)
+}
+
+// ToEnv returns "NAME=value" pairs containing the current values of all registered struct fields
+// (and flags registered in the wrapped FlagSet directly) except nil pointer fields.
+// Names of the variables are specified by `flagEnv` tags or derived from the primary flag names: they are
+// converted to upper case, characters other than letters and digits are replaced with "_" and `prefix` is added.
+// Unlike ToJSON(), values of the fields tagged with `flagSecret` are not redacted to be passed to child processes.
+// Values of slice flags are comma-joined (as returned by flag.Value.String()) and can't be read back by
+// AddDotEnvFile(): it sets a variable value as a single element
+func (fls *FlagSet) ToEnv(prefix string) []string {
+	var res []string
+	for _, value := range fls.getEffectiveFlagValues() {
+		res = append(res, fls.getFlagEnvVarName(prefix, value.flagName)+"="+value.value)
+	}
+	return res
+}
+
+// getPositionalArgs returns the value of the registered `flagArgs` field or FlagSet.Args()
+// if there is no such field
+func (fls *FlagSet) getPositionalArgs() []string {
+	for _, structFields := range fls.registeredFields {
+		for _, info := range structFields.flagArgsToSet {
+			return getAccessibleValue(info.fieldValue).Convert(reflect.TypeOf([]string(nil))).Interface().([]string)
+		}
+	}
+	return fls.Args()
+}
+
+// getEnvVarName converts the flag name to the environment variable name
+func getEnvVarName(prefix string, flagName string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, flagName)
+}
+
+func isBoolFlagValue(value flag.Value) bool {
+	boolFlag, ok := value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
@@ -0,0 +1,137 @@
+package flago
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type effectiveConfigTestPerson struct {
+	Name  string  `flag:"name"`
+	Email *string `flag:"email"`
+}
+
+type effectiveConfigTestStruct struct {
+	Verbose  int                       `flags:"verbose,v"`
+	Login    *string                   `flag:"login"`
+	Token    *string                   `flag:"token"`
+	Debug    bool                      `flag:"debug"`
+	Color    bool                      `flag:"color"`
+	Timeout  time.Duration             `flag:"timeout"`
+	Sender   effectiveConfigTestPerson `flagPrefix:"sender-"`
+	FileList []string                  `flagArgs:"true"`
+}
+
+func newEffectiveConfigTestFlagSet(t *testing.T, args []string) (*FlagSet, *effectiveConfigTestStruct) {
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.Float64("ratio", 0.5, "")
+	structVal := &effectiveConfigTestStruct{
+		Color:   true,
+		Timeout: time.Second,
+	}
+	require.NoError(t, fls.StructVar(structVal))
+	require.NoError(t, fls.Parse(args))
+	return fls, structVal
+}
+
+func TestToArgs(t *testing.T) {
+	args := []string{
+		"-v", "2", "--login=admin", "-debug", "-color=false", "-sender-email", "a@b.c", "-ratio", "1",
+		"--", "-file1", "file2",
+	}
+	fls, structVal := newEffectiveConfigTestFlagSet(t, args)
+	canonicalArgs := []string{
+		"-color=false", "-debug", "-login", "admin", "-ratio", "1", "-sender-email", "a@b.c", "-verbose", "2",
+		"--", "-file1", "file2",
+	}
+	require.Equal(t, canonicalArgs, fls.ToArgs())
+
+	reproducedFls, reproducedVal := newEffectiveConfigTestFlagSet(t, fls.ToArgs())
+	require.Equal(t, structVal, reproducedVal)
+	require.Equal(t, canonicalArgs, reproducedFls.ToArgs())
+
+	structVal.FileList = []string{"file3"}
+	structVal.Timeout = time.Minute
+	require.Equal(t, []string{
+		"-color=false", "-debug", "-login", "admin", "-ratio", "1", "-sender-email", "a@b.c",
+		"-timeout", "1m0s", "-verbose", "2", "file3",
+	}, fls.ToArgs())
+}
+
+func TestToJSON(t *testing.T) {
+	fls, _ := newEffectiveConfigTestFlagSet(t, []string{"-v", "2", "-login", "admin", "-sender-name", "John"})
+	jsonBytes, err := fls.ToJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"verbose": 2,
+		"login": "admin",
+		"debug": false,
+		"color": true,
+		"timeout": "1s",
+		"ratio": 0.5,
+		"sender": {
+			"name": "John"
+		}
+	}`, string(jsonBytes))
+}
+
+func TestToJSONKeysInJSONSchema(t *testing.T) {
+	type testStruct struct {
+		Mode   string                    `flags:"old-mode,mode" flagDeprecatedAliases:"old-mode"`
+		Hidden string                    `flag:"hidden" flagHidden:"true"`
+		Slice  []int                     `flag:"slice"`
+		Sender effectiveConfigTestPerson `flagPrefix:"sender-"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.Bool("direct", false, "")
+	require.NoError(t, fls.StructVar(&testStruct{Sender: effectiveConfigTestPerson{Email: ptr("a@b.c")}}))
+	jsonBytes, err := fls.ToJSON()
+	require.NoError(t, err)
+	var values map[string]any
+	require.NoError(t, json.Unmarshal(jsonBytes, &values))
+	schemaBuf := bytes.Buffer{}
+	require.NoError(t, fls.WriteJSONSchema(&schemaBuf))
+	var schema jsonSchema
+	require.NoError(t, json.Unmarshal(schemaBuf.Bytes(), &schema))
+
+	var checkKeys func(values map[string]any, schema *jsonSchema)
+	checkKeys = func(values map[string]any, schema *jsonSchema) {
+		for key, value := range values {
+			require.Contains(t, schema.Properties, key)
+			if object, isObject := value.(map[string]any); isObject {
+				checkKeys(object, schema.Properties[key])
+			}
+		}
+	}
+	checkKeys(values, &schema)
+	require.NotContains(t, values, "hidden")
+	require.Len(t, values, len(schema.Properties))
+}
+
+func TestToJSONKeyCollision(t *testing.T) {
+	type testStruct struct {
+		Sender  bool                      `flag:"sender"`
+		Details effectiveConfigTestPerson `flagPrefix:"sender-"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	_, err := fls.ToJSON()
+	require.EqualError(t, err, `key "sender" is used by both a flag and the flags with "sender-" prefix`)
+}
+
+func TestToEnv(t *testing.T) {
+	fls, _ := newEffectiveConfigTestFlagSet(t, []string{"-v", "2", "-sender-email", "a@b.c"})
+	require.Equal(t, []string{
+		"APP_COLOR=true",
+		"APP_DEBUG=false",
+		"APP_RATIO=0.5",
+		"APP_SENDER_EMAIL=a@b.c",
+		"APP_SENDER_NAME=",
+		"APP_TIMEOUT=1s",
+		"APP_VERBOSE=2",
+	}, fls.ToEnv("APP_"))
+}
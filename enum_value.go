@@ -0,0 +1,49 @@
+package flago
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// enumValue wraps a flag.Value registered for a field with `flagEnum` tag. Set() rejects values that
+// are not in the enum, so they never reach the field
+type enumValue struct {
+	flag.Value
+	enum []string
+}
+
+func (v *enumValue) Set(value string) error {
+	for _, allowedValue := range v.enum {
+		if value == allowedValue {
+			return v.Value.Set(value)
+		}
+	}
+	return fmt.Errorf(`%w, allowed values: "%s"`, ErrNotAllowedValue, strings.Join(v.enum, `", "`))
+}
+
+// String can be called on a zero value (see isZeroDefaultValue)
+func (v *enumValue) String() string {
+	if v.Value == nil {
+		return ""
+	}
+	return v.Value.String()
+}
+
+func (v *enumValue) IsBoolFlag() bool {
+	return isBoolFlagValue(v.Value)
+}
+
+// unwrapFlagValue returns the flag.Value wrapped by enumValue or the value itself if it's not wrapped
+func unwrapFlagValue(value flag.Value) flag.Value {
+	if ev, ok := value.(*enumValue); ok {
+		return ev.Value
+	}
+	return value
+}
+
+// unwrapFlagValues temporarily replaces enumValue values of the flags with the wrapped values to let
+// the std flag package recognize their types. Returns a function restoring the values
+func unwrapFlagValues(flagSet *flag.FlagSet) (restore func()) {
+	return replaceFlagValues(flagSet, unwrapFlagValue)
+}
@@ -8,13 +8,20 @@ import (
 )
 
 const (
-	flagNameTag     = "flag"
-	flagRequiredTag = "flagRequired"
-	flagNamesTag    = "flags"
-	flagArgsTag     = "flagArgs"
-	flagUsageTag    = "flagUsage"
-	flagUsagePrefix = "flagUsagePrefix"
-	flagPrefixTag   = "flagPrefix"
+	flagNameTag              = "flag"
+	flagRequiredTag          = "flagRequired"
+	flagNamesTag             = "flags"
+	flagArgsTag              = "flagArgs"
+	flagUsageTag             = "flagUsage"
+	flagUsagePrefix          = "flagUsagePrefix"
+	flagPrefixTag            = "flagPrefix"
+	flagHiddenTag            = "flagHidden"
+	flagDeprecatedTag        = "flagDeprecated"
+	flagDeprecatedAliasesTag = "flagDeprecatedAliases"
+	flagEnumTag              = "flagEnum"
+	flagSecretTag            = "flagSecret"
+	flagEnvTag               = "flagEnv"
+	flagUnknownTag           = "flagUnknown"
 )
 
 type fieldRole interface {
@@ -27,7 +34,23 @@ type namedFlagRole struct {
 	usage       string
 	roleTagName string
 	isRequired  bool
-	isBool      bool
+	// hasRequiredTag indicates that isRequired is set explicitly by `flagRequired` tag
+	hasRequiredTag bool
+	isBool         bool
+	isHidden       bool
+	isSecret       bool
+	// deprecation is a message printed if any of flagNames is passed. Empty if the field is not deprecated
+	deprecation string
+	// deprecatedAliases is a subset of flagNames that are deprecated
+	deprecatedAliases []string
+	// enum contains allowed flag values. Empty if any value is allowed
+	enum []string
+	// envVarName is a name of the environment variable (e.g. in `.env` file) providing the flag value.
+	// Empty if the name should be derived from the flag name
+	envVarName string
+	// isAutoNamed indicates that the field has no `flag` or `flags` tag, and its name is derived
+	// by the naming strategy
+	isAutoNamed bool
 }
 
 func (r namedFlagRole) getRoleTagName() string {
@@ -39,11 +62,35 @@ func (r namedFlagRole) withPrefixes(namePrefix string, usagePrefix string) named
 		for i, name := range r.flagNames {
 			r.flagNames[i] = namePrefix + name
 		}
+		for i, name := range r.deprecatedAliases {
+			r.deprecatedAliases[i] = namePrefix + name
+		}
 	}
 	r.usage = usagePrefix + r.usage
 	return r
 }
 
+// getUsedFlagNames returns flag names that are not deprecated aliases. The first one is the primary name
+func (r namedFlagRole) getUsedFlagNames() []string {
+	if len(r.deprecatedAliases) == 0 {
+		return r.flagNames
+	}
+	res := make([]string, 0, len(r.flagNames)-len(r.deprecatedAliases))
+	for _, name := range r.flagNames {
+		isDeprecatedAlias := false
+		for _, alias := range r.deprecatedAliases {
+			if name == alias {
+				isDeprecatedAlias = true
+				break
+			}
+		}
+		if !isDeprecatedAlias {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
 type flagArgsRole struct {
 }
 
@@ -51,24 +98,45 @@ func (r flagArgsRole) getRoleTagName() string {
 	return flagArgsTag
 }
 
+type flagUnknownRole struct {
+}
+
+func (r flagUnknownRole) getRoleTagName() string {
+	return flagUnknownTag
+}
+
 type nestedStructRole struct {
 	flagPrefix  string
 	usagePrefix string
+	// autoNamePart is the Go name of the field without tags that is a nested struct if the naming strategy
+	// is set. Empty if the field has `flagPrefix` tag
+	autoNamePart string
+	// isEmbedded indicates that the field is an embedded struct (or a pointer to struct) without tags.
+	// Its flags are promoted to the parent struct
+	isEmbedded bool
 }
 
 func (r nestedStructRole) getRoleTagName() string {
 	return flagPrefixTag
 }
 
-func getFieldRole(field reflect.StructField) (fieldRole, error) {
+// getFieldRole returns the role of the field according to its tags.
+// If `autoFlagName` is not empty, the field without behavior tags is treated as a flag with this name
+// (or as a nested struct if it's a struct)
+func getFieldRole(field reflect.StructField, autoFlagName string) (fieldRole, error) {
 	var (
 		flagName        string
 		flagNames       []string
 		flagArgs        bool
+		flagUnknown     bool
 		flagRequired    bool
 		hasFlagRequired bool
 		flagPrefix      string
 		hasFlagPrefix   bool
+		flagHidden      bool
+		hasFlagHidden   bool
+		flagSecret      bool
+		hasFlagSecret   bool
 		err             error
 	)
 	tags := field.Tag
@@ -81,33 +149,58 @@ func getFieldRole(field reflect.StructField) (fieldRole, error) {
 	if flagArgs, _, err = getBoolTag(tags, flagArgsTag); err != nil {
 		return nil, err
 	}
+	if flagUnknown, _, err = getBoolTag(tags, flagUnknownTag); err != nil {
+		return nil, err
+	}
 	if flagRequired, hasFlagRequired, err = getBoolTag(tags, flagRequiredTag); err != nil {
 		return nil, err
 	}
+	if flagHidden, hasFlagHidden, err = getBoolTag(tags, flagHiddenTag); err != nil {
+		return nil, err
+	}
+	if flagSecret, hasFlagSecret, err = getBoolTag(tags, flagSecretTag); err != nil {
+		return nil, err
+	}
 
 	flagPrefix, hasFlagPrefix = tags.Lookup(flagPrefixTag)
 
 	hasFlagName := flagName != ""
 	hasFlagNames := len(flagNames) > 0
+	isEmbeddedStruct := field.Anonymous && tags.Get(flagNameTag) != "-" &&
+		!hasFlagName && !hasFlagNames && !hasFlagPrefix && !flagArgs && !flagUnknown &&
+		isEmbeddedStructType(field.Type)
+	isAutoNestedStruct, isAutoNamed := false, false
+	if autoFlagName != "" && !isEmbeddedStruct && !hasFlagName && !hasFlagNames && !hasFlagPrefix && !flagArgs && !flagUnknown {
+		if isAutoNestedStructType(field.Type) {
+			isAutoNestedStruct = true
+		} else {
+			flagName, hasFlagName, isAutoNamed = autoFlagName, true, true
+		}
+	}
 
 	behaviorTagsCount := trueCount(
 		hasFlagName,
 		hasFlagNames,
-		hasFlagPrefix,
+		hasFlagPrefix || isAutoNestedStruct || isEmbeddedStruct,
 		flagArgs,
+		flagUnknown,
 	)
 	if behaviorTagsCount == 0 {
 		return nil, nil
 	}
 	if behaviorTagsCount > 1 {
 		return nil, fmt.Errorf(
-			`only one of "%s", "%s", "%s", "%s" tags can be used`,
-			flagNameTag, flagNamesTag, flagArgsTag, flagPrefixTag,
+			`only one of "%s", "%s", "%s", "%s", "%s" tags can be used`,
+			flagNameTag, flagNamesTag, flagArgsTag, flagUnknownTag, flagPrefixTag,
 		)
 	}
 
 	usage, hasUsage := tags.Lookup(flagUsageTag)
 	usagePrefix, hasUsagePrefix := tags.Lookup(flagUsagePrefix)
+	deprecation, hasDeprecation := tags.Lookup(flagDeprecatedTag)
+	deprecatedAliases := getCommaSeparatedTag(tags, flagDeprecatedAliasesTag)
+	enum := getCommaSeparatedTag(tags, flagEnumTag)
+	envVarName, hasEnvVarName := tags.Lookup(flagEnvTag)
 
 	if hasUsagePrefix && !hasFlagPrefix {
 		return nil, fmt.Errorf(`"%s" tag can be used only with "%s" tag`, flagUsagePrefix, flagPrefixTag)
@@ -115,8 +208,21 @@ func getFieldRole(field reflect.StructField) (fieldRole, error) {
 
 	if hasFlagName || hasFlagNames {
 		role := namedFlagRole{
-			usage:      usage,
-			isRequired: flagRequired,
+			usage:          usage,
+			isRequired:     flagRequired,
+			hasRequiredTag: hasFlagRequired,
+			isHidden:       flagHidden,
+			isSecret:       flagSecret,
+			deprecation:    deprecation,
+			enum:           enum,
+			envVarName:     envVarName,
+			isAutoNamed:    isAutoNamed,
+		}
+		if hasEnvVarName && envVarName == "" {
+			return nil, fmt.Errorf(`"%s" tag should contain a variable name`, flagEnvTag)
+		}
+		if hasDeprecation && deprecation == "" {
+			return nil, fmt.Errorf(`"%s" tag should contain a message`, flagDeprecatedTag)
 		}
 		if hasFlagName {
 			role.flagNames = []string{flagName}
@@ -125,12 +231,24 @@ func getFieldRole(field reflect.StructField) (fieldRole, error) {
 			role.flagNames = flagNames
 			role.roleTagName = flagNamesTag
 		}
+		if len(deprecatedAliases) > 0 {
+			if err := checkDeprecatedAliases(role.flagNames, deprecatedAliases); err != nil {
+				return nil, err
+			}
+			role.deprecatedAliases = deprecatedAliases
+		}
 		return role, nil
 	}
 
 	for tagName, hasTag := range map[string]bool{
-		flagUsageTag:    hasUsage,
-		flagRequiredTag: hasFlagRequired,
+		flagUsageTag:             hasUsage,
+		flagRequiredTag:          hasFlagRequired,
+		flagHiddenTag:            hasFlagHidden,
+		flagDeprecatedTag:        hasDeprecation,
+		flagDeprecatedAliasesTag: len(deprecatedAliases) > 0,
+		flagEnumTag:              len(enum) > 0,
+		flagSecretTag:            hasFlagSecret,
+		flagEnvTag:               hasEnvVarName,
 	} {
 		if hasTag {
 			return nil, fmt.Errorf(
@@ -147,10 +265,22 @@ func getFieldRole(field reflect.StructField) (fieldRole, error) {
 		}, nil
 	}
 
+	if isEmbeddedStruct {
+		return nestedStructRole{isEmbedded: true}, nil
+	}
+
+	if isAutoNestedStruct {
+		return nestedStructRole{autoNamePart: field.Name}, nil
+	}
+
 	if flagArgs {
 		return flagArgsRole{}, nil
 	}
 
+	if flagUnknown {
+		return flagUnknownRole{}, nil
+	}
+
 	// should never happen
 	return nil, nil
 }
@@ -167,7 +297,11 @@ func getBoolTag(tags reflect.StructTag, tagName string) (val bool, exists bool,
 }
 
 func getFlagNames(tags reflect.StructTag) []string {
-	namesStr := tags.Get(flagNamesTag)
+	return getCommaSeparatedTag(tags, flagNamesTag)
+}
+
+func getCommaSeparatedTag(tags reflect.StructTag, tagName string) []string {
+	namesStr := tags.Get(tagName)
 	if namesStr == "" {
 		return nil
 	}
@@ -180,6 +314,26 @@ func getFlagNames(tags reflect.StructTag) []string {
 	return names
 }
 
+func checkDeprecatedAliases(flagNames []string, deprecatedAliases []string) error {
+	for _, alias := range deprecatedAliases {
+		isFlagName := false
+		for _, flagName := range flagNames {
+			if alias == flagName {
+				isFlagName = true
+				break
+			}
+		}
+		if !isFlagName {
+			return fmt.Errorf(`"%s" tag contains "%s" that is not listed in "%s" tag`,
+				flagDeprecatedAliasesTag, alias, flagNamesTag)
+		}
+	}
+	if len(deprecatedAliases) >= len(flagNames) {
+		return fmt.Errorf(`"%s" tag should leave at least one not deprecated name`, flagDeprecatedAliasesTag)
+	}
+	return nil
+}
+
 func trueCount(values ...bool) (res int) {
 	for _, v := range values {
 		if v {
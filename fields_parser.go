@@ -1,8 +1,11 @@
 package flago
 
 import (
+	"flag"
 	"fmt"
 	"reflect"
+	"strings"
+	"unicode"
 	"unsafe"
 
 	"github.com/cardinalby/go-struct-flags/stdutil"
@@ -13,7 +16,105 @@ type fieldInfo struct {
 	fieldName     string
 	namedFlagRole *namedFlagRole
 	isFlagArgs    bool
+	isFlagUnknown bool
 	fieldValue    reflect.Value
+	// groups is a chain of nested structs (from outer to inner) containing the field
+	groups []flagsGroup
+	// lazyStruct is the innermost nil struct pointer containing the field. Nil if there is no such pointer
+	lazyStruct *lazyStruct
+}
+
+// flagsGroup describes a nested struct tagged with non-empty `flagPrefix`
+type flagsGroup struct {
+	// key is a name of the group derived from its flag prefix
+	key string
+	// flagPrefix is a resulting prefix of flag names in the group including prefixes of the parents
+	flagPrefix string
+}
+
+// parentStructInfo describes a struct containing the fields being collected
+type parentStructInfo struct {
+	flagPrefix  string
+	usagePrefix string
+	fieldName   string
+	groups      []flagsGroup
+	// namingStrategy derives names of the fields without tags. Nil if such fields are ignored
+	namingStrategy NamingStrategy
+	// autoNamePath contains Go names of the nested structs without `flagPrefix` tags since the last
+	// struct with `flagPrefix` tag. Used by namingStrategy
+	autoNamePath []string
+	// onlyFields contains the fields that should be registered. Nil if all fields should be registered
+	onlyFields map[fieldPointer]bool
+	// isSelected indicates that the struct is in onlyFields or is nested in such struct
+	isSelected bool
+	// isAutoNested indicates that the struct is nested by the naming strategy or is nested in such struct
+	isAutoNested      bool
+	requiredByDefault bool
+	// lazyStruct is the innermost nil struct pointer containing the struct. Nil if there is no such pointer
+	lazyStruct *lazyStruct
+}
+
+// nested returns parentStructInfo for the struct contained in `fieldName` field of the parent
+func (p parentStructInfo) nested(fieldName string, role nestedStructRole) parentStructInfo {
+	res := parentStructInfo{
+		flagPrefix:        p.flagPrefix + role.flagPrefix,
+		usagePrefix:       p.usagePrefix + role.usagePrefix,
+		fieldName:         fieldName,
+		groups:            p.groups,
+		namingStrategy:    p.namingStrategy,
+		onlyFields:        p.onlyFields,
+		requiredByDefault: p.requiredByDefault,
+		lazyStruct:        p.lazyStruct,
+		isAutoNested:      p.isAutoNested || role.autoNamePart != "",
+	}
+	if role.autoNamePart != "" {
+		res.autoNamePath = append(p.autoNamePath[:len(p.autoNamePath):len(p.autoNamePath)], role.autoNamePart)
+	}
+	if key := getFlagsGroupKey(role.flagPrefix); key != "" {
+		res.groups = append(res.groups[:len(res.groups):len(res.groups)], flagsGroup{
+			key:        key,
+			flagPrefix: res.flagPrefix,
+		})
+	}
+	return res
+}
+
+// isFieldSelected returns false if the field should be skipped because of OnlyFields option
+func (p parentStructInfo) isFieldSelected(fieldValue reflect.Value) bool {
+	if p.onlyFields == nil {
+		return true
+	}
+	_, isListed := p.onlyFields[newFieldPointer(fieldValue)]
+	return isListed || p.isSelected
+}
+
+// markFieldMatched marks the field pointer in onlyFields as matched. It should be called only for
+// the fields having a role (flags or nested structs), so pointers to other fields are reported
+// by checkFieldPointersMatched
+func (p parentStructInfo) markFieldMatched(fieldValue reflect.Value) {
+	if p.onlyFields == nil {
+		return
+	}
+	fieldPtr := newFieldPointer(fieldValue)
+	if _, isListed := p.onlyFields[fieldPtr]; isListed {
+		p.onlyFields[fieldPtr] = true
+	}
+}
+
+// getAutoFlagName returns the flag name derived from the field name by the naming strategy or
+// empty string if the field should not be named automatically
+func (p parentStructInfo) getAutoFlagName(field reflect.StructField) string {
+	if p.namingStrategy == nil || !field.IsExported() || field.Tag.Get(flagNameTag) == "-" {
+		return ""
+	}
+	return p.namingStrategy(append(p.autoNamePath[:len(p.autoNamePath):len(p.autoNamePath)], field.Name))
+}
+
+// getFlagsGroupKey returns the flag prefix without trailing separators
+func getFlagsGroupKey(flagPrefix string) string {
+	return strings.TrimRightFunc(flagPrefix, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
 }
 
 // collectFieldsInfoRecursive collects info about all fields of the given struct including nested
@@ -21,9 +122,7 @@ type fieldInfo struct {
 // is invalid.
 func collectFieldsInfoRecursive(
 	structValue reflect.Value,
-	parentFlagPrefix string,
-	parentUsagePrefix string,
-	parentFieldName string,
+	parent parentStructInfo,
 	ignoredFields map[unsafe.Pointer]struct{},
 	flagsToIgnore stdutil.FormalTagNames, // to be filled
 ) (res []fieldInfo, err error) {
@@ -31,22 +130,26 @@ func collectFieldsInfoRecursive(
 	for i := 0; i < structValue.NumField(); i++ {
 		fieldVal := structValue.Field(i)
 		_, isIgnored := ignoredFields[fieldVal.Addr().UnsafePointer()]
+		isSelected := parent.isFieldSelected(fieldVal)
 
 		field := sValType.Field(i)
-		fieldName := getFieldName(parentFieldName, field.Name)
-		fieldRole, err := getFieldRole(field)
+		fieldName := getFieldName(parent.fieldName, field.Name)
+		fieldRole, err := getFieldRole(field, parent.getAutoFlagName(field))
 		if err != nil {
 			return nil, fmt.Errorf(`field "%s": %w`, fieldName, err)
 		}
 		if fieldRole == nil {
 			continue
 		}
+		parent.markFieldMatched(fieldVal)
+		if _, isNested := fieldRole.(nestedStructRole); !isNested && !isSelected {
+			continue
+		}
 		if fieldInfo, err := collectFieldInfo(
 			field,
 			fieldVal,
 			fieldName,
-			parentFlagPrefix,
-			parentUsagePrefix,
+			parent,
 			fieldRole,
 			ignoredFields,
 			flagsToIgnore,
@@ -64,15 +167,28 @@ func collectFieldInfo(
 	field reflect.StructField,
 	fieldValue reflect.Value,
 	fieldName string,
-	parentFlagPrefix string,
-	parentUsagePrefix string,
+	parent parentStructInfo,
 	fieldRole fieldRole,
 	ignoredFields map[unsafe.Pointer]struct{},
 	flagsToIgnore stdutil.FormalTagNames,
 	isIgnored bool,
 ) (res []fieldInfo, err error) {
 	defer func() {
-		if err != nil {
+		if err == nil {
+			return
+		}
+		// the hint is added only for the outermost field named or nested by the naming strategy
+		skipHint := ""
+		if !parent.isAutoNested {
+			skipHint = ` (use flag:"-" tag to skip it)`
+		}
+		if namedRole, isNamed := fieldRole.(namedFlagRole); isNamed && namedRole.isAutoNamed {
+			err = fmt.Errorf(`field "%s" named by the naming strategy: %w%s`, fieldName, err, skipHint)
+		} else if nestedRole, isNested := fieldRole.(nestedStructRole); isNested && nestedRole.autoNamePart != "" {
+			err = fmt.Errorf(`field "%s" nested by the naming strategy: %w%s`, fieldName, err, skipHint)
+		} else if nestedRole, isNested := fieldRole.(nestedStructRole); isNested && nestedRole.isEmbedded {
+			err = fmt.Errorf(`embedded field "%s": %w`, fieldName, err)
+		} else {
 			err = fmt.Errorf(`field "%s" tagged with "%s": %w`, fieldName, fieldRole.getRoleTagName(), err)
 		}
 	}()
@@ -83,11 +199,17 @@ func collectFieldInfo(
 		if err := checkPrefixedFieldType(fieldType); err != nil {
 			return nil, err
 		}
+		nestedParent := parent.nested(fieldName, role)
+		nestedParent.isSelected = parent.isFieldSelected(fieldValue)
+		structValue := fieldValue
+		if fieldType.Kind() == reflect.Ptr {
+			if structValue, err = nestedParent.dereferenceStructPointer(fieldValue); err != nil {
+				return nil, err
+			}
+		}
 		if nestedRes, err := collectFieldsInfoRecursive(
-			fieldValue,
-			parentFlagPrefix+role.flagPrefix,
-			parentUsagePrefix+role.usagePrefix,
-			fieldName,
+			structValue,
+			nestedParent,
 			ignoredFields,
 			flagsToIgnore,
 		); err != nil {
@@ -103,9 +225,25 @@ func collectFieldInfo(
 			fieldName:  fieldName,
 			isFlagArgs: true,
 			fieldValue: fieldValue,
+			groups:     parent.groups,
+			lazyStruct: parent.lazyStruct,
+		})
+	case flagUnknownRole:
+		if err := checkFlagUnknownFieldType(fieldType); err != nil {
+			return nil, err
+		}
+		res = append(res, fieldInfo{
+			fieldName:     fieldName,
+			isFlagUnknown: true,
+			fieldValue:    fieldValue,
+			groups:        parent.groups,
+			lazyStruct:    parent.lazyStruct,
 		})
 	case namedFlagRole:
-		role = role.withPrefixes(parentFlagPrefix, parentUsagePrefix)
+		role = role.withPrefixes(parent.flagPrefix, parent.usagePrefix)
+		if parent.requiredByDefault && !role.hasRequiredTag {
+			role.isRequired = true
+		}
 		if isIgnored {
 			for _, flagName := range role.flagNames {
 				if _, has := flagsToIgnore[flagName]; has {
@@ -125,16 +263,21 @@ func collectFieldInfo(
 			fieldName:     fieldName,
 			namedFlagRole: &role,
 			fieldValue:    fieldValue,
+			groups:        parent.groups,
+			lazyStruct:    parent.lazyStruct,
 		})
 	}
 	return res, nil
 }
 
 func checkPrefixedFieldType(fieldType reflect.Type) error {
-	if fieldType.Kind() != reflect.Struct {
-		return fmt.Errorf("struct expected, got %s", fieldType.Name())
+	if fieldType.Kind() == reflect.Struct {
+		return nil
 	}
-	return nil
+	if fieldType.Kind() == reflect.Ptr && fieldType.Elem().Kind() == reflect.Struct {
+		return nil
+	}
+	return fmt.Errorf("struct or pointer to struct expected, got %s", fieldType.String())
 }
 
 func checkFlagArgsFieldType(fieldType reflect.Type) error {
@@ -144,6 +287,14 @@ func checkFlagArgsFieldType(fieldType reflect.Type) error {
 	return nil
 }
 
+func checkFlagUnknownFieldType(fieldType reflect.Type) error {
+	if reflect.TypeOf([]string(nil)).ConvertibleTo(fieldType) ||
+		reflect.TypeOf(map[string][]string(nil)).ConvertibleTo(fieldType) {
+		return nil
+	}
+	return fmt.Errorf("[]string or map[string][]string expected, got %s", fieldType.String())
+}
+
 func getFieldName(parentFieldName, fieldName string) string {
 	if parentFieldName == "" {
 		return fieldName
@@ -151,6 +302,16 @@ func getFieldName(parentFieldName, fieldName string) string {
 	return fmt.Sprintf("%s.%s", parentFieldName, fieldName)
 }
 
+// isEmbeddedStructType returns true if an embedded field of the type without tags should be treated as
+// a nested struct promoting its flags
+func isEmbeddedStructType(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Struct &&
+		!reflect.PointerTo(fieldType).Implements(reflect.TypeOf((*flag.Value)(nil)).Elem())
+}
+
 func isBoolFlagField(value reflect.Value) bool {
 	if value.Type().Kind() == reflect.Bool {
 		return true
@@ -160,3 +321,9 @@ func isBoolFlagField(value reflect.Value) bool {
 	}
 	return false
 }
+
+// getAccessibleValue returns the addressable value that can be accessed even if it's obtained
+// from unexported struct field
+func getAccessibleValue(value reflect.Value) reflect.Value {
+	return reflect.NewAt(value.Type(), value.Addr().UnsafePointer()).Elem()
+}
@@ -0,0 +1,85 @@
+package flago
+
+import (
+	"flag"
+	"sort"
+)
+
+// flagField describes a registered struct field or a flag registered in the wrapped FlagSet directly
+type flagField struct {
+	// flagNames contains the primary flag name followed by aliases (excluding deprecated ones)
+	flagNames []string
+	flag      *flag.Flag
+	// namedFlagsField is nil for flags registered in the wrapped FlagSet directly
+	namedFlagsField *registeredNamedFlagsField
+}
+
+// getFieldPath returns dot-separated path of the struct field or empty string if the flag
+// is registered in the wrapped FlagSet directly
+func (f flagField) getFieldPath() string {
+	if f.namedFlagsField == nil {
+		return ""
+	}
+	return f.namedFlagsField.info.fieldName
+}
+
+func (f flagField) getGroups() []flagsGroup {
+	if f.namedFlagsField == nil {
+		return nil
+	}
+	return f.namedFlagsField.info.groups
+}
+
+// getFlagFields returns all registered flag fields sorted by the primary flag names
+func (fls *FlagSet) getFlagFields() (res []flagField) {
+	structFlagNames := make(map[string]struct{})
+	for _, structFields := range fls.registeredFields {
+		for fieldName := range structFields.namedFlagFields {
+			namedFlagsField := structFields.namedFlagFields[fieldName]
+			for _, namedFlagField := range namedFlagsField.fields {
+				structFlagNames[namedFlagField.flagName] = struct{}{}
+			}
+			flagNames := namedFlagsField.info.namedFlagRole.getUsedFlagNames()
+			if f := fls.Lookup(flagNames[0]); f != nil {
+				res = append(res, flagField{
+					flagNames:       flagNames,
+					flag:            f,
+					namedFlagsField: &namedFlagsField,
+				})
+			}
+		}
+	}
+
+	directFlagsIndexes := make(map[flag.Value]int)
+	fls.VisitAll(func(f *flag.Flag) {
+		if _, isStructFlag := structFlagNames[f.Name]; isStructFlag {
+			return
+		}
+		if i, seen := directFlagsIndexes[f.Value]; seen {
+			res[i].flagNames = append(res[i].flagNames, f.Name)
+			return
+		}
+		directFlagsIndexes[f.Value] = len(res)
+		res = append(res, flagField{
+			flagNames: []string{f.Name},
+			flag:      f,
+		})
+	})
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].flagNames[0] < res[j].flagNames[0]
+	})
+	return res
+}
+
+// getFlagGroups returns groups of the struct field registered with the given primary flag name
+func (fls *FlagSet) getFlagGroups(flagName string) []flagsGroup {
+	for _, structFields := range fls.registeredFields {
+		for _, namedFlagsField := range structFields.namedFlagFields {
+			if namedFlagsField.info.namedFlagRole.getUsedFlagNames()[0] == flagName {
+				return namedFlagsField.info.groups
+			}
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"unsafe"
 
@@ -16,6 +17,7 @@ import (
 var ErrFlagRedefined = errors.New("flag redefined")
 var ErrIsRequired = errors.New("flag is required")
 var ErrMultipleAliases = errors.New("multiple aliases for the same flag are used")
+var ErrNotAllowedValue = errors.New("not allowed value")
 
 type registeredNamedFlagField struct {
 	flagName     string
@@ -26,6 +28,9 @@ type registeredNamedFlagsField struct {
 	fields     []registeredNamedFlagField
 	isRequired bool
 	isZero     bool
+	// isOptional indicates that the field is a pointer that is assigned only if any of the flags is parsed
+	isOptional bool
+	info       fieldInfo
 }
 
 // structRegisteredFields contains instruction for finishing parsing of a struct
@@ -33,14 +38,17 @@ type registeredNamedFlagsField struct {
 type structRegisteredFields struct {
 	// keys: field names, values: slice where each element corresponds to a registered flag (with different names)
 	namedFlagFields map[string]registeredNamedFlagsField
-	// keys: field names, values: field values that should be assigned with FlagSet.ArgStrings()
-	flagArgsToSet map[string]reflect.Value
+	// keys: field names, values: fields that should be assigned with FlagSet.ArgStrings()
+	flagArgsToSet map[string]fieldInfo
+	// keys: field names, values: fields that should be assigned with ignored unknown flags
+	flagUnknownToSet map[string]fieldInfo
 }
 
 func newStructRegisteredFields() structRegisteredFields {
 	return structRegisteredFields{
-		namedFlagFields: make(map[string]registeredNamedFlagsField),
-		flagArgsToSet:   make(map[string]reflect.Value),
+		namedFlagFields:  make(map[string]registeredNamedFlagsField),
+		flagArgsToSet:    make(map[string]fieldInfo),
+		flagUnknownToSet: make(map[string]fieldInfo),
 	}
 }
 
@@ -49,22 +57,47 @@ type FlagSet struct {
 	*flag.FlagSet
 	// registeredFields contains instructions for finishing parsing of the registered structs
 	// key is a pointer to a struct
-	registeredFields                  map[any]structRegisteredFields
-	requiredFlagNames                 map[string]struct{}
+	registeredFields  map[any]structRegisteredFields
+	requiredFlagNames map[string]struct{}
+	hiddenFlagNames   map[string]struct{}
+	secretFlagNames   map[string]struct{}
+	// keys: deprecated flag names, values: deprecation messages
+	deprecatedFlagNames map[string]string
+	// keys: flag names, values: names of env variables specified in `flagEnv` tags
+	envVarNames                       map[string]string
 	ignoreUnknown                     bool
 	ignoreUnknownTreatAmbiguousAsBool bool
 	flagsToIgnore                     stdutil.FormalTagNames
+	unknownFlagsManifest              stdutil.FormalTagNames
 	allowParsingMultipleAliases       bool
+	unknownFlagSuggestions            bool
+	prefixMatching                    bool
+	nameNormalizer                    cmdargs.NameNormalizer
+	namingStrategy                    NamingStrategy
 	ignoredArgs                       []string
+	ignoredEntries                    []cmdargs.Entry
+	// args passed to the wrapped FlagSet during the last call to Parse()
+	parsedArgs cmdargs.Args
+	// args passed to the last call to Parse() and indexes of parsedArgs.Args in them
+	// (nil if parsedArgs are the args passed to Parse())
+	originalArgs          cmdargs.Args
+	originalArgIndexes    []int
+	usageWidth            int
+	responseFileExpansion bool
+	sources               []prioritizedSource
 }
 
 // Wrap creates a new FlagSet wrapping the given `stdFlagSet` and does not set stdFlagSet.Usage
 func Wrap(stdFlagSet *flag.FlagSet) *FlagSet {
 	return &FlagSet{
-		FlagSet:           stdFlagSet,
-		registeredFields:  make(map[any]structRegisteredFields),
-		flagsToIgnore:     make(stdutil.FormalTagNames),
-		requiredFlagNames: make(map[string]struct{}),
+		FlagSet:             stdFlagSet,
+		registeredFields:    make(map[any]structRegisteredFields),
+		flagsToIgnore:       make(stdutil.FormalTagNames),
+		requiredFlagNames:   make(map[string]struct{}),
+		hiddenFlagNames:     make(map[string]struct{}),
+		secretFlagNames:     make(map[string]struct{}),
+		deprecatedFlagNames: make(map[string]string),
+		envVarNames:         make(map[string]string),
 	}
 }
 
@@ -87,6 +120,40 @@ func (fls *FlagSet) SetAllowParsingMultipleAliases(allow bool) {
 	fls.allowParsingMultipleAliases = allow
 }
 
+// SetPrefixMatching sets the behavior of Parse() when an unknown flag name is passed.
+// If `true`, the name that is a unique prefix of a registered flag name is resolved to it
+// (`--verb` is treated as `--verbose`), similar to GNU getopt_long. Exact matches always win,
+// "h" and "help" are never resolved. If the prefix is ambiguous, Parse() returns AmbiguousFlagError.
+// If `false`, such names are unknown flags.
+// Default value is `false`.
+func (fls *FlagSet) SetPrefixMatching(enabled bool) {
+	fls.prefixMatching = enabled
+}
+
+// SetNameNormalizer sets the behavior of Parse() when an unknown flag name is passed.
+// If `normalizer` is not nil, the name is resolved to the registered flag having the same name after
+// normalization (e.g. use cmdargs.FoldCaseAndSeparators to match `--Log_Level` to `--log-level`).
+// Exact matches always win. If the name matches multiple flags, Parse() returns AmbiguousFlagError.
+// It also applies to stripping unknown flags (see SetIgnoreUnknown). Usage help message shows registered names.
+// Default value is `nil`.
+func (fls *FlagSet) SetNameNormalizer(normalizer cmdargs.NameNormalizer) {
+	fls.nameNormalizer = normalizer
+}
+
+// SetNamingStrategy sets the behavior of StructVar() for exported fields without `flag`, `flags`,
+// `flagPrefix`, `flagArgs` and `flagUnknown` tags.
+// If `strategy` is not nil, flag names are derived from the field names (e.g. KebabCase, SnakeCase, CamelCase).
+// Fields that are structs without tags are treated as nested structs, their names are included in the
+// names of their fields ("Server.MaxConns" -> "server-max-conns"). Other tags (e.g. `flagUsage`) can be used
+// with such fields. Explicit `flag` and `flags` tags override derived names, `flag:"-"` disables naming
+// of the field.
+// If `nil`, fields without tags are ignored.
+// It affects subsequent StructVar() calls.
+// Default value is `nil`.
+func (fls *FlagSet) SetNamingStrategy(strategy NamingStrategy) {
+	fls.namingStrategy = strategy
+}
+
 // SetIgnoreUnknown sets the behavior of Parse() when unknown flags are passed.
 // If `true`, they will be ignored.
 // If `false`, Parse() will return an error.
@@ -110,12 +177,67 @@ func (fls *FlagSet) SetIgnoreUnknownAmbiguousAsBoolFlags(treatAsBool bool) {
 	fls.ignoreUnknownTreatAmbiguousAsBool = treatAsBool
 }
 
+// SetUnknownFlagsManifest specifies known types of flags that are expected to be unknown (e.g. flags of
+// a downstream tool the ignored flags are passed to) if SetIgnoreUnknown(true) is set.
+// `manifest` keys are flag names, values indicate bool flags. It can be obtained from another
+// flag.FlagSet using stdutil.GetFormalFlagNames() or loaded from a file using stdutil.LoadFormalTagNames().
+// Ambiguous unknown flags listed in the manifest are classified according to it, others are treated
+// according to SetIgnoreUnknownAmbiguousAsBoolFlags(). Types of the flags registered in FlagSet take precedence
+// over the manifest
+func (fls *FlagSet) SetUnknownFlagsManifest(manifest stdutil.FormalTagNames) {
+	fls.unknownFlagsManifest = manifest.Clone()
+}
+
+// GetSecretFlagNames returns sorted names of the flags registered for fields tagged with `flagSecret`.
+// Use them with cmdargs.Args.RedactFlags() to hide secret values before logging args
+func (fls *FlagSet) GetSecretFlagNames() []string {
+	res := make([]string, 0, len(fls.secretFlagNames))
+	for flagName := range fls.secretFlagNames {
+		res = append(res, flagName)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// SetResponseFileExpansion sets the behavior of Parse() when `@path` args are passed.
+// If `true`, each such arg is replaced with args read from the file at `path` before parsing
+// (see cmdargs.ExpandResponseFiles).
+// If `false`, they are parsed as usual args.
+// Default value is `false`.
+func (fls *FlagSet) SetResponseFileExpansion(enabled bool) {
+	fls.responseFileExpansion = enabled
+}
+
+// SetUnknownFlagSuggestions sets the behavior of Parse() when an unknown flag is passed.
+// If `true`, UnknownFlagError contains the closest registered flag names (including aliases and prefixed
+// names of nested structs, except hidden ones) in Suggestions, and its message ends with
+// "(did you mean -name?)". Names are compared using Damerau-Levenshtein distance.
+// Default value is `false`.
+func (fls *FlagSet) SetUnknownFlagSuggestions(enabled bool) {
+	fls.unknownFlagSuggestions = enabled
+}
+
+// SetUsageWidth sets the width of the help message printed by PrintDefaults().
+// If `width` > 0, flag names are aligned in a column and usage messages are wrapped to fit the width.
+// If `width` == UsageWidthAuto, the terminal width is taken from COLUMNS environment variable.
+// If `width` == 0, the help message has the same format as the std flag package produces.
+// Default value is 0.
+func (fls *FlagSet) SetUsageWidth(width int) {
+	fls.usageWidth = width
+}
+
 // GetIgnoredArgs returns a slice of arguments that were ignored during the last call to Parse()
 // because of SetIgnoreUnknown(true), nil otherwise
 func (fls *FlagSet) GetIgnoredArgs() []string {
 	return fls.ignoredArgs
 }
 
+// GetIgnoredEntries returns flags that were ignored during the last call to Parse()
+// because of SetIgnoreUnknown(true), nil otherwise. All entries are cmdargs.FlagEntry
+func (fls *FlagSet) GetIgnoredEntries() []cmdargs.Entry {
+	return fls.ignoredEntries
+}
+
 // Parse parses the command-line flags calling Parse on the wrapped FlagSet
 // and then sets values of the registered structs fields for flags that were actually parsed.
 func (fls *FlagSet) Parse(arguments []string) error {
@@ -123,49 +245,99 @@ func (fls *FlagSet) Parse(arguments []string) error {
 		return errors.New("wrapped FlagSet is nil")
 	}
 	fls.ignoredArgs = nil
+	fls.ignoredEntries = nil
+	resetSliceValues(fls.FlagSet)
+	originalArguments := arguments
+	var argIndexes []int
+	if fls.responseFileExpansion {
+		var err error
+		if arguments, argIndexes, err = cmdargs.ExpandResponseFilesWithIndexes(arguments); err != nil {
+			return fls.handleParseError(err)
+		}
+	}
 	if fls.ignoreUnknown {
-		argsPassed, argsIgnored := cmdargs.NewArgs(arguments).
+		argsPassed, passedIndexes, entriesIgnored := cmdargs.NewArgs(arguments).
 			WithFlagSet(fls.FlagSet).
 			WithAmbiguousAsBool(fls.ignoreUnknownTreatAmbiguousAsBool).
-			StripUnknownFlags(
-				fls.flagsToIgnore,
+			WithPrefixMatching(fls.prefixMatching).
+			WithNameNormalizer(fls.nameNormalizer).
+			StripUnknownFlagEntriesWithIndexes(
+				fls.getIgnoredFlagsWithKnownType(),
 			)
-		arguments, fls.ignoredArgs = argsPassed.Args, argsIgnored.Args
+		arguments, fls.ignoredEntries = argsPassed.Args, entriesIgnored
+		argIndexes = composeArgIndexes(argIndexes, passedIndexes)
+		for _, entry := range entriesIgnored {
+			fls.ignoredArgs = append(fls.ignoredArgs, entry.TokenStrings()...)
+		}
 	}
-	if err := fls.FlagSet.Parse(arguments); err != nil {
-		return err
+	fls.setParsedArgs(originalArguments, arguments, argIndexes)
+	resolvedArgs, flags, err := fls.validateFlagArgs(arguments)
+	if err == nil {
+		err = fls.parseWrappedFlagSet(resolvedArgs, flags)
+	}
+	if err != nil {
+		return fls.handleParseError(err)
+	}
+	fls.warnDeprecatedFlags()
+	err = fls.applySources()
+	if err == nil {
+		err = fls.postProcessRegisteredFields()
 	}
-	if err := fls.postProcessRegisteredFields(); err != nil {
-		// follow the same error handling policy as the wrapped FlagSet
+	if err != nil {
+		return fls.handleParseError(err)
+	}
+	return nil
+}
+
+// getIgnoredFlagsWithKnownType returns the unknown flags manifest merged with flags from ignored fields
+func (fls *FlagSet) getIgnoredFlagsWithKnownType() stdutil.FormalTagNames {
+	if len(fls.unknownFlagsManifest) == 0 {
+		return fls.flagsToIgnore
+	}
+	res := fls.unknownFlagsManifest.Clone()
+	for flagName, isBoolFlag := range fls.flagsToIgnore {
+		res[flagName] = isBoolFlag
+	}
+	return res
+}
+
+// handleParseError follows the same error handling policy as the wrapped FlagSet
+func (fls *FlagSet) handleParseError(err error) error {
+	if !errors.Is(err, flag.ErrHelp) {
 		_, _ = fmt.Fprintln(fls.Output(), err.Error())
-		fls.usage()
-
-		switch fls.ErrorHandling() {
-		case flag.ContinueOnError:
-			return err
-		case flag.ExitOnError:
-			if errors.Is(err, flag.ErrHelp) {
-				os.Exit(0)
-			}
-			os.Exit(2)
-		case flag.PanicOnError:
-			panic(err)
-		}
+	}
+	fls.usage()
+
+	switch fls.ErrorHandling() {
+	case flag.ContinueOnError:
 		return err
+	case flag.ExitOnError:
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
 	}
-	return nil
+	return err
 }
 
 // StructVar registers the fields of the given struct as a flags
 // `ignoredFields` is a slice of pointers to fields that should be ignored and not registered as flags
 func (fls *FlagSet) StructVar(p any, ignoredFields ...any) error {
-	return fls.StructVarWithPrefix(p, "", ignoredFields...)
+	return fls.StructVarOpts(p, IgnoreFields(ignoredFields...))
 }
 
 // StructVarWithPrefix registers the fields of the given struct as a flags
 // with names prefixed with `flagsPrefix`
 // `ignoredFields` is a slice of pointers to fields that should be ignored and not registered as flags
-func (fls *FlagSet) StructVarWithPrefix(p any, flagsPrefix string, ignoredFields ...any) (err error) {
+func (fls *FlagSet) StructVarWithPrefix(p any, flagsPrefix string, ignoredFields ...any) error {
+	return fls.StructVarOpts(p, WithPrefix(flagsPrefix), IgnoreFields(ignoredFields...))
+}
+
+// StructVarOpts registers the fields of the given struct as a flags customizing the registration with
+// `opts` (see IgnoreFields, OnlyFields, WithPrefix, WithUsagePrefix, WithRequiredByDefault, WithNamingStrategy)
+func (fls *FlagSet) StructVarOpts(p any, opts ...Option) (err error) {
 	defer func() {
 		err = fls.recoverParsePanic(recover(), err)
 	}()
@@ -177,28 +349,45 @@ func (fls *FlagSet) StructVarWithPrefix(p any, flagsPrefix string, ignoredFields
 	if err != nil {
 		return err
 	}
-	ignoredFieldsMap, err := newIgnoredFieldsMap(ignoredFields)
+	options := structVarOptions{namingStrategy: fls.namingStrategy}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	ignoredFieldsMap, err := newIgnoredFieldsMap(options.ignoredFields)
 	if err != nil {
 		return fmt.Errorf("invalid ignoredFields: %w", err)
 	}
+	onlyFieldsSet, err := newFieldPointersSet(options.onlyFields)
+	if err != nil {
+		return fmt.Errorf("invalid onlyFields: %w", err)
+	}
 
 	// collect fields info but don't register flags until all fields are validated
 	fieldsInfo, err := collectFieldsInfoRecursive(
 		structValue,
-		flagsPrefix,
-		"",
-		"",
+		parentStructInfo{
+			flagPrefix:        options.flagsPrefix,
+			usagePrefix:       options.usagePrefix,
+			namingStrategy:    options.namingStrategy,
+			onlyFields:        onlyFieldsSet,
+			requiredByDefault: options.requiredByDefault,
+		},
 		ignoredFieldsMap,
 		fls.flagsToIgnore,
 	)
 	if err != nil {
 		return err
 	}
+	if err := checkFieldPointersMatched(options.onlyFields, onlyFieldsSet); err != nil {
+		return fmt.Errorf("invalid onlyFields: %w", err)
+	}
 
 	postParseActions := newStructRegisteredFields()
 	for _, info := range fieldsInfo {
 		if info.isFlagArgs {
-			postParseActions.flagArgsToSet[info.fieldName] = info.fieldValue
+			postParseActions.flagArgsToSet[info.fieldName] = info
+		} else if info.isFlagUnknown {
+			postParseActions.flagUnknownToSet[info.fieldName] = info
 		} else if info.namedFlagRole != nil {
 			postParseActions.namedFlagFields[info.fieldName] = fls.registerNamedFlagField(info)
 		}
@@ -209,13 +398,19 @@ func (fls *FlagSet) StructVarWithPrefix(p any, flagsPrefix string, ignoredFields
 	return nil
 }
 
-// PrintDefaults prints the default FlagSet usage to wrapped FlagSet.Output grouping alternative flag names
+// PrintDefaults prints the default FlagSet usage to wrapped FlagSet.Output grouping alternative flag names.
+// See SetUsageWidth for formatting options
 func (fls *FlagSet) PrintDefaults() {
-	PrintFlagSetDefaults(fls)
+	if fls.usageWidth == 0 {
+		PrintFlagSetDefaults(fls)
+	} else {
+		PrintFlagSetAlignedDefaults(fls, fls.usageWidth)
+	}
 }
 
 func (fls *FlagSet) postProcessRegisteredFields() error {
 	existingFlagNames := stdutil.GetExistingFlagNames(fls.FlagSet)
+	usedLazyStructs := fls.getUsedLazyStructs(existingFlagNames)
 	var errs []error
 
 	for _, structFields := range fls.registeredFields {
@@ -231,12 +426,10 @@ func (fls *FlagSet) postProcessRegisteredFields() error {
 					if fieldFirstFoundFlagName == "" {
 						fieldFirstFoundFlagName = namedFlagField.flagName
 					} else if fieldFirstFoundFlagName != namedFlagField.flagName {
-						errs = append(errs, fmt.Errorf(
-							`%w: "%s" and "%s"`,
-							ErrMultipleAliases,
-							fieldFirstFoundFlagName,
-							namedFlagField.flagName,
-						))
+						errs = append(errs, fls.newMultipleAliasesArgsError(&MultipleAliasesError{
+							First:  fieldFirstFoundFlagName,
+							Second: namedFlagField.flagName,
+						}))
 						continue
 					}
 				}
@@ -244,30 +437,48 @@ func (fls *FlagSet) postProcessRegisteredFields() error {
 					namedFlagField.postParseClb()
 				}
 			}
-			if namedFlagsField.isRequired && !isAnyFieldFlagFound {
+			if namedFlagsField.isRequired && !isAnyFieldFlagFound &&
+				isLazyStructUsed(namedFlagsField.info.lazyStruct, usedLazyStructs) {
 				names := make([]string, len(namedFlagsField.fields))
 				for i, namedFlagField := range namedFlagsField.fields {
 					names[i] = namedFlagField.flagName
 				}
-				errs = append(errs, fmt.Errorf(`%w: "%s"`, ErrIsRequired, strings.Join(names, `"/"`)))
+				errs = append(errs, &RequiredFlagError{Names: names})
 			}
 		}
 		if len(errs) == 0 {
-			for _, fieldValue := range structFields.flagArgsToSet {
-				fieldValue.Set(reflect.ValueOf(fls.FlagSet.Args()))
+			for _, info := range structFields.flagArgsToSet {
+				info.fieldValue.Set(reflect.ValueOf(fls.FlagSet.Args()))
+			}
+			for _, info := range structFields.flagUnknownToSet {
+				setUnknownFlagsField(info.fieldValue, fls.ignoredEntries)
 			}
 		}
 	}
 	if len(errs) > 0 {
 		return joinErr(errs...)
 	}
+	for s := range usedLazyStructs {
+		s.pointerValue.Set(s.structPtr)
+	}
 	return nil
 }
 
+// warnDeprecatedFlags prints a warning to the output for each parsed deprecated flag
+func (fls *FlagSet) warnDeprecatedFlags() {
+	fls.FlagSet.Visit(func(f *flag.Flag) {
+		if deprecation, isDeprecated := fls.deprecatedFlagNames[f.Name]; isDeprecated {
+			_, _ = fmt.Fprintf(fls.Output(), "flag -%s is deprecated: %s\n", f.Name, deprecation)
+		}
+	})
+}
+
+// usage calls Usage of the wrapped FlagSet. If it's nil or the default implementation of the flag package
+// (e.g. in the FlagSet passed to Wrap()), DefaultUsage is called instead to hide hidden flags, deprecated
+// aliases and defaults of the secret flags
 func (fls *FlagSet) usage() {
-	if fls.Usage == nil {
-		printUsageTitle(fls.FlagSet, fls.FlagSet.Name())
-		fls.FlagSet.PrintDefaults()
+	if fls.Usage == nil || isStdDefaultUsage(fls.Usage) {
+		DefaultUsage(fls)
 	} else {
 		fls.Usage()
 	}
@@ -285,7 +496,9 @@ func (fls *FlagSet) sprintf(format string, a ...any) string {
 }
 
 func (fls *FlagSet) registerNamedFlagField(info fieldInfo) (res registeredNamedFlagsField) {
+	res.info = info
 	isZero := false
+	var fieldEnumValue *enumValue
 	for _, flagName := range info.namedFlagRole.flagNames {
 		registeredNamedFlagField := registeredNamedFlagField{
 			flagName: flagName,
@@ -293,7 +506,16 @@ func (fls *FlagSet) registerNamedFlagField(info fieldInfo) (res registeredNamedF
 		registeredNamedFlagField.postParseClb, isZero = info.namedFlagRole.varRegister(
 			fls.FlagSet, flagName, info.namedFlagRole.usage,
 		)
+		if enum := info.namedFlagRole.enum; len(enum) > 0 {
+			// aliases share the same value to be grouped in usage
+			f := fls.FlagSet.Lookup(flagName)
+			if fieldEnumValue == nil {
+				fieldEnumValue = &enumValue{Value: f.Value, enum: enum}
+			}
+			f.Value = fieldEnumValue
+		}
 		res.fields = append(res.fields, registeredNamedFlagField)
+		res.isOptional = registeredNamedFlagField.postParseClb != nil
 	}
 	res.isRequired = info.namedFlagRole.isRequired && isZero
 	if res.isRequired {
@@ -301,6 +523,42 @@ func (fls *FlagSet) registerNamedFlagField(info fieldInfo) (res registeredNamedF
 			fls.requiredFlagNames[flagName] = struct{}{}
 		}
 	}
+	if info.namedFlagRole.isHidden {
+		for _, flagName := range info.namedFlagRole.flagNames {
+			fls.hiddenFlagNames[flagName] = struct{}{}
+		}
+	}
+	if info.namedFlagRole.isSecret {
+		for _, flagName := range info.namedFlagRole.flagNames {
+			fls.secretFlagNames[flagName] = struct{}{}
+		}
+	}
+	if info.namedFlagRole.envVarName != "" {
+		for _, flagName := range info.namedFlagRole.flagNames {
+			fls.envVarNames[flagName] = info.namedFlagRole.envVarName
+		}
+	}
+	if info.namedFlagRole.deprecation != "" {
+		for _, flagName := range info.namedFlagRole.flagNames {
+			fls.deprecatedFlagNames[flagName] = info.namedFlagRole.deprecation
+		}
+	}
+	if deprecatedAliases := info.namedFlagRole.deprecatedAliases; len(deprecatedAliases) > 0 {
+		isDeprecatedAlias := make(map[string]bool, len(deprecatedAliases))
+		for _, alias := range deprecatedAliases {
+			isDeprecatedAlias[alias] = true
+		}
+		for _, flagName := range info.namedFlagRole.flagNames {
+			if !isDeprecatedAlias[flagName] {
+				for _, alias := range deprecatedAliases {
+					if _, isFieldDeprecated := fls.deprecatedFlagNames[alias]; !isFieldDeprecated {
+						fls.deprecatedFlagNames[alias] = fmt.Sprintf(`use "-%s" instead`, flagName)
+					}
+				}
+				break
+			}
+		}
+	}
 	return res
 }
 
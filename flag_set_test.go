@@ -3,11 +3,18 @@ package flago
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"flag"
+	"io"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cardinalby/go-struct-flags/cmdargs"
+	"github.com/cardinalby/go-struct-flags/stdutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -283,6 +290,26 @@ func TestInvalidFlagArgsTagValue(t *testing.T) {
 	require.Error(t, fls.StructVarWithPrefix(&structVal, ""))
 }
 
+func TestFlagEnvWithoutFlagName(t *testing.T) {
+	type nested struct {
+		S string `flag:"s"`
+	}
+	type prefixStruct struct {
+		N nested `flagPrefix:"n-" flagEnv:"N"`
+	}
+	type argsStruct struct {
+		Args []string `flagArgs:"true" flagEnv:"ARGS"`
+	}
+	type unknownStruct struct {
+		Unknown []string `flagUnknown:"true" flagEnv:"UNKNOWN"`
+	}
+	for _, structPtr := range []any{&prefixStruct{}, &argsStruct{}, &unknownStruct{}} {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		err := fls.StructVar(structPtr)
+		require.ErrorContains(t, err, `"flagEnv" tag can be used only with "flag" or "flags" tags`)
+	}
+}
+
 func TestInvalidFlagArgsTogetherWithFlagName(t *testing.T) {
 	type invalidStruct struct {
 		A string `flag:"a" flagArgs:"true"`
@@ -620,6 +647,89 @@ func TestInvalidFlagPrefixFieldType(t *testing.T) {
 	fls := Wrap(flagSet)
 	structVal := invalidStruct{}
 	require.Error(t, fls.StructVar(&structVal))
+
+	type invalidPointerStruct struct {
+		A *string `flagPrefix:"a"`
+	}
+	require.ErrorContains(
+		t,
+		Wrap(flag.NewFlagSet("", flag.ContinueOnError)).StructVar(&invalidPointerStruct{}),
+		"struct or pointer to struct expected, got *string",
+	)
+}
+
+func TestOptionalNestedStructPointer(t *testing.T) {
+	type proxyStruct struct {
+		Addr string `flag:"addr"`
+	}
+	type tlsStruct struct {
+		Cert  string       `flag:"cert" flagRequired:"true"`
+		Key   string       `flag:"key"`
+		Proxy *proxyStruct `flagPrefix:"proxy-"`
+	}
+	type parentStruct struct {
+		TLS  *tlsStruct `flagPrefix:"tls-" flagUsagePrefix:"TLS "`
+		Name string     `flag:"name"`
+	}
+	newFlagSet := func(structVal *parentStruct) *FlagSet {
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		require.NoError(t, fls.StructVar(structVal))
+		return fls
+	}
+
+	t.Run("not passed", func(t *testing.T) {
+		structVal := parentStruct{}
+		fls := newFlagSet(&structVal)
+		require.NoError(t, fls.Parse([]string{"-name", "n"}))
+		require.Nil(t, structVal.TLS)
+		require.Equal(t, "n", structVal.Name)
+		require.Equal(t, []string{"-name", "n"}, fls.ToArgs())
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		structVal := parentStruct{}
+		fls := newFlagSet(&structVal)
+		require.NoError(t, fls.Parse([]string{"-tls-cert", "c.pem"}))
+		require.Equal(t, &tlsStruct{Cert: "c.pem"}, structVal.TLS)
+		require.Equal(t, []string{"-tls-cert", "c.pem"}, fls.ToArgs())
+	})
+
+	t.Run("nested pointer passed", func(t *testing.T) {
+		structVal := parentStruct{}
+		fls := newFlagSet(&structVal)
+		require.NoError(t, fls.Parse([]string{"-tls-cert", "c.pem", "-tls-proxy-addr", "a:1"}))
+		require.Equal(t, &tlsStruct{Cert: "c.pem", Proxy: &proxyStruct{Addr: "a:1"}}, structVal.TLS)
+	})
+
+	t.Run("required flag in used group", func(t *testing.T) {
+		structVal := parentStruct{}
+		fls := newFlagSet(&structVal)
+		captureOutput(fls, func() {
+			var requiredErr *RequiredFlagError
+			require.ErrorAs(t, fls.Parse([]string{"-tls-proxy-addr", "a:1"}), &requiredErr)
+			require.Equal(t, []string{"tls-cert"}, requiredErr.Names)
+		})
+		require.Nil(t, structVal.TLS)
+	})
+
+	t.Run("provided by source", func(t *testing.T) {
+		structVal := parentStruct{}
+		fls := newFlagSet(&structVal)
+		fls.AddSource(SourceFunc(func(flagName string, _ string) (string, bool, error) {
+			return "src.pem", flagName == "tls-cert", nil
+		}), 0)
+		require.NoError(t, fls.Parse(nil))
+		require.Equal(t, &tlsStruct{Cert: "src.pem"}, structVal.TLS)
+	})
+
+	t.Run("not nil", func(t *testing.T) {
+		tls := &tlsStruct{Cert: "default.pem"}
+		structVal := parentStruct{TLS: tls}
+		fls := newFlagSet(&structVal)
+		require.NoError(t, fls.Parse(nil))
+		require.Same(t, tls, structVal.TLS)
+		require.Equal(t, "default.pem", structVal.TLS.Cert)
+	})
 }
 
 func TestInvalidNestedStruct(t *testing.T) {
@@ -681,7 +791,148 @@ func TestEmbedStructNoTags(t *testing.T) {
 	structVal := simpleStruct{}
 	fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
 	require.NoError(t, fls.StructVarWithPrefix(&structVal, "p-"))
-	require.Error(t, fls.Parse([]string{"--p-x", "val1", "--p-y", "val2"}))
+	require.NoError(t, fls.Parse([]string{"--p-x", "val1", "--p-y", "val2"}))
+	require.Equal(t, "val1", structVal.X)
+	require.Equal(t, "val2", structVal.Y)
+}
+
+func TestEmbedStructsPromotedFlags(t *testing.T) {
+	type LoggingFlags struct {
+		LogLevel string `flag:"log-level"`
+	}
+	type HTTPFlags struct {
+		Port int `flag:"port"`
+	}
+	type skippedFlags struct {
+		Z string `flag:"z"`
+	}
+	type ServiceFlags struct {
+		LoggingFlags
+		HTTPFlags
+		skippedFlags `flag:"-"`
+		Name         string
+	}
+	structVal := ServiceFlags{}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(KebabCase)
+	require.NoError(t, fls.StructVar(&structVal))
+	require.Nil(t, fls.Lookup("z"))
+	require.NoError(t, fls.Parse([]string{"--log-level", "debug", "--port", "8080", "--name", "svc"}))
+	require.Equal(t, "debug", structVal.LogLevel)
+	require.Equal(t, 8080, structVal.Port)
+	require.Equal(t, "svc", structVal.Name)
+}
+
+func TestEmbedStructsConflictingFlags(t *testing.T) {
+	type A struct {
+		X string `flag:"x"`
+	}
+	type B struct {
+		X string `flag:"x"`
+	}
+	type simpleStruct struct {
+		A
+		B
+	}
+	fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+	require.ErrorIs(t, fls.StructVar(&simpleStruct{}), ErrFlagRedefined)
+}
+
+type recursiveNode struct {
+	*recursiveNode
+	Name string `flag:"name"`
+}
+
+func TestEmbedStructRecursivePointer(t *testing.T) {
+	fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+	require.ErrorContains(t, fls.StructVar(&recursiveNode{}), "recursive nil pointer to flago.recursiveNode")
+}
+
+func TestEmbedStructPointerArgsAndUnknown(t *testing.T) {
+	type Sub struct {
+		Args    []string `flagArgs:"true"`
+		Unknown []string `flagUnknown:"true"`
+	}
+	type simpleStruct struct {
+		*Sub
+		X string `flag:"x"`
+	}
+
+	structVal := simpleStruct{}
+	fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"-x", "val"}))
+	require.Nil(t, structVal.Sub)
+
+	structVal = simpleStruct{}
+	fls = Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"-x", "val", "a", "b"}))
+	require.Equal(t, &Sub{Args: []string{"a", "b"}}, structVal.Sub)
+
+	structVal = simpleStruct{}
+	fls = Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+	fls.SetIgnoreUnknown(true)
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"-y=1"}))
+	require.NotNil(t, structVal.Sub)
+	require.Empty(t, structVal.Args)
+	require.Equal(t, []string{"-y=1"}, structVal.Unknown)
+}
+
+func TestEmbedStructPointer(t *testing.T) {
+	type TLSFlags struct {
+		Cert string `flag:"cert" flagRequired:"true"`
+		Key  string `flag:"key"`
+	}
+	type simpleStruct struct {
+		*TLSFlags
+		X string `flag:"x"`
+	}
+
+	t.Run("not passed", func(t *testing.T) {
+		structVal := simpleStruct{}
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		require.NoError(t, fls.StructVar(&structVal))
+		require.NoError(t, fls.Parse([]string{"-x", "val"}))
+		require.Equal(t, "val", structVal.X)
+		require.Nil(t, structVal.TLSFlags)
+		require.Equal(t, []string{"-x", "val"}, fls.ToArgs())
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		structVal := simpleStruct{}
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		require.NoError(t, fls.StructVar(&structVal))
+		require.NoError(t, fls.Parse([]string{"-cert", "c.pem"}))
+		require.NotNil(t, structVal.TLSFlags)
+		require.Equal(t, "c.pem", structVal.Cert)
+		require.Equal(t, "", structVal.Key)
+		require.Equal(t, []string{"-cert", "c.pem"}, fls.ToArgs())
+	})
+
+	t.Run("required flag is missing", func(t *testing.T) {
+		structVal := simpleStruct{}
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		captureOutput(fls, func() {
+			require.NoError(t, fls.StructVar(&structVal))
+			var requiredErr *RequiredFlagError
+			require.ErrorAs(t, fls.Parse([]string{"-key", "k.pem"}), &requiredErr)
+			require.Equal(t, []string{"cert"}, requiredErr.Names)
+		})
+		require.Nil(t, structVal.TLSFlags)
+	})
+
+	t.Run("not nil", func(t *testing.T) {
+		tlsFlags := &TLSFlags{Cert: "default.pem"}
+		structVal := simpleStruct{TLSFlags: tlsFlags}
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		require.NoError(t, fls.StructVar(&structVal))
+		require.NoError(t, fls.Parse([]string{"-key", "k.pem"}))
+		require.Same(t, tlsFlags, structVal.TLSFlags)
+		require.Equal(t, "default.pem", structVal.Cert)
+		require.Equal(t, "k.pem", structVal.Key)
+	})
 }
 
 func TestEmbedStructWithPrefix(t *testing.T) {
@@ -780,3 +1031,680 @@ func TestMultiNamesFlagSetUsage(t *testing.T) {
 	)
 	require.Error(t, parseErr)
 }
+
+func TestHiddenFlag(t *testing.T) {
+	type testStruct struct {
+		S string `flag:"s" flagUsage:"usage_s"`
+		H string `flags:"h1,h2" flagUsage:"usage_h" flagHidden:"true"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+
+	require.Equal(t, "Usage:\n  -s string\n    \tusage_s\n", captureOutput(fls, fls.Usage))
+	fls.SetUsageWidth(40)
+	require.Equal(t, "Usage:\n  -s string  usage_s\n", captureOutput(fls, fls.Usage))
+
+	require.NoError(t, fls.Parse([]string{"-h2", "abc"}))
+	require.Equal(t, "abc", structVal.H)
+}
+
+func TestDeprecatedFlag(t *testing.T) {
+	type testStruct struct {
+		S   string `flag:"s" flagUsage:"usage_s"`
+		Old string `flag:"old" flagUsage:"usage_old" flagDeprecated:"use -s"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+
+	require.Equal(t,
+		"Usage:\n  -old string\n    \tusage_old (deprecated: use -s)\n  -s string\n    \tusage_s\n",
+		captureOutput(fls, fls.Usage),
+	)
+	fls.SetUsageWidth(80)
+	require.Equal(t,
+		"Usage:\n  -old string  usage_old (deprecated: use -s)\n  -s string    usage_s\n",
+		captureOutput(fls, fls.Usage),
+	)
+
+	require.Equal(t, "", captureOutput(fls, func() {
+		require.NoError(t, fls.Parse([]string{"-s", "abc"}))
+	}))
+	require.Equal(t, "flag -old is deprecated: use -s\n", captureOutput(fls, func() {
+		require.NoError(t, fls.Parse([]string{"-old", "def"}))
+	}))
+	require.Equal(t, "abc", structVal.S)
+	require.Equal(t, "def", structVal.Old)
+}
+
+func TestDeprecatedAliases(t *testing.T) {
+	type testStruct struct {
+		S string `flags:"old-s,s,o" flagUsage:"usage_s" flagDeprecatedAliases:"old-s,o"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVarWithPrefix(&structVal, "p-"))
+
+	require.Equal(t,
+		"Usage:\n  -p-s string\n    \tusage_s (deprecated aliases: -p-o, -p-old-s)\n",
+		captureOutput(fls, fls.Usage),
+	)
+	fls.SetUsageWidth(80)
+	require.Equal(t,
+		"Usage:\n  -p-s string  usage_s (deprecated aliases: -p-o, -p-old-s)\n",
+		captureOutput(fls, fls.Usage),
+	)
+	require.Equal(t, "flag -p-o is deprecated: use \"-p-s\" instead\n", captureOutput(fls, func() {
+		require.NoError(t, fls.Parse([]string{"-p-o", "abc"}))
+	}))
+	require.Equal(t, "abc", structVal.S)
+}
+
+func TestHiddenAndDeprecatedWrappedUsage(t *testing.T) {
+	type testStruct struct {
+		S  string `flags:"s,old" flagUsage:"usage_s" flagDeprecatedAliases:"old"`
+		H2 string `flag:"h2" flagHidden:"true"`
+	}
+	fls := Wrap(flag.NewFlagSet("app", flag.ContinueOnError))
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	var parseErr error
+	output := captureOutput(fls, func() {
+		parseErr = fls.Parse([]string{"-x"})
+	})
+	require.Error(t, parseErr)
+	require.Equal(t,
+		"flag provided but not defined: -x\nUsage of app:\n  -s string\n    \tusage_s (deprecated aliases: -old)\n",
+		output,
+	)
+}
+
+func TestInvalidDeprecatedAliases(t *testing.T) {
+	fls := NewFlagSet("", flag.ContinueOnError)
+	unknownAlias := struct {
+		S string `flags:"s,o" flagDeprecatedAliases:"x"`
+	}{}
+	require.Error(t, fls.StructVar(&unknownAlias))
+
+	allAliases := struct {
+		S string `flags:"s,o" flagDeprecatedAliases:"s,o"`
+	}{}
+	require.Error(t, fls.StructVar(&allAliases))
+
+	noMessage := struct {
+		S string `flag:"s" flagDeprecated:""`
+	}{}
+	require.Error(t, fls.StructVar(&noMessage))
+
+	notNamedFlag := struct {
+		S []string `flagArgs:"true" flagHidden:"true"`
+	}{}
+	require.Error(t, fls.StructVar(&notNamedFlag))
+}
+
+func TestEnumFlag(t *testing.T) {
+	type testStruct struct {
+		Format *string `flags:"format,f" flagEnum:"json, text"`
+	}
+	newFlagSet := func(structVal *testStruct) *FlagSet {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		fls.Usage = func() {}
+		require.NoError(t, fls.StructVar(structVal))
+		return fls
+	}
+
+	structVal := testStruct{}
+	require.NoError(t, newFlagSet(&structVal).Parse([]string{"-f", "text"}))
+	require.Equal(t, "text", *structVal.Format)
+
+	structVal = testStruct{}
+	fls := newFlagSet(&structVal)
+	var err error
+	_ = captureOutput(fls, func() {
+		err = fls.Parse([]string{"-format", "xml"})
+	})
+	require.ErrorIs(t, err, ErrNotAllowedValue)
+	require.ErrorContains(t, err, `invalid value "xml" for flag -format: not allowed value, allowed values: "json", "text"`)
+	require.Nil(t, structVal.Format)
+}
+
+func TestEnumFlagValueNotAssigned(t *testing.T) {
+	type testStruct struct {
+		Mode string `flags:"mode,m" flagEnum:"fast,slow" flagUsage:"speed"`
+	}
+	structVal := testStruct{Mode: "fast"}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&structVal))
+
+	var err error
+	output := captureOutput(fls, func() {
+		err = fls.Parse([]string{"-mode", "zzz"})
+	})
+	var invalidValueErr *InvalidValueError
+	require.ErrorAs(t, err, &invalidValueErr)
+	require.Equal(t, "mode", invalidValueErr.Flag)
+	require.Equal(t, "zzz", invalidValueErr.Value)
+	require.ErrorIs(t, err, ErrNotAllowedValue)
+	require.Equal(t, "fast", structVal.Mode)
+	// usage shows the type of the wrapped value
+	require.Contains(t, output, `-m -mode string`)
+	require.Contains(t, output, `(default "fast")`)
+
+	require.Equal(t,
+		"Usage:\n  -m -mode string\n    \tspeed (default \"fast\") (allowed: \"fast\", \"slow\")\n",
+		captureOutput(fls, fls.Usage),
+	)
+	fls.SetUsageWidth(80)
+	require.Equal(t,
+		"Usage:\n  -m -mode string  speed (default \"fast\") (allowed: \"fast\", \"slow\")\n",
+		captureOutput(fls, fls.Usage),
+	)
+
+	require.ErrorIs(t, fls.Set("m", "zzz"), ErrNotAllowedValue)
+	require.Equal(t, "fast", structVal.Mode)
+	require.NoError(t, fls.Set("m", "slow"))
+	require.Equal(t, "slow", structVal.Mode)
+}
+
+func TestSecretFlag(t *testing.T) {
+	type testStruct struct {
+		Password string `flag:"password" flagUsage:"usage_p" flagSecret:"true"`
+		Pin      int    `flag:"pin" flagSecret:"true"`
+		P        int    `flag:"p"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{
+		Password: "qwerty",
+		Pin:      1234,
+	}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.Equal(t, []string{"password", "pin"}, fls.GetSecretFlagNames())
+
+	require.Equal(t,
+		"Usage:\n  -p int\n    \t\n  -password string\n    \tusage_p\n  -pin int\n    \t\n",
+		captureOutput(fls, fls.Usage),
+	)
+
+	var parseErr error
+	output := captureOutput(fls, func() {
+		parseErr = fls.Parse([]string{"-pin", "secret"})
+	})
+	expectedErrorMsg := `invalid value "******" for flag -pin: parse error`
+	require.EqualError(t, parseErr, expectedErrorMsg)
+	require.True(t, strings.HasPrefix(output, expectedErrorMsg+"\nUsage:\n"))
+
+	output = captureOutput(fls, func() {
+		parseErr = fls.Parse([]string{"-p", "not_secret"})
+	})
+	require.EqualError(t, parseErr, `invalid value "not_secret" for flag -p: parse error`)
+
+	fls.SetUsageWidth(80)
+	require.Equal(t,
+		"Usage:\n  -p int\n  -password string  usage_p\n  -pin int\n",
+		captureOutput(fls, fls.Usage),
+	)
+
+	jsonBytes, err := fls.ToJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"password": "******", "pin": "******", "p": 0}`, string(jsonBytes))
+}
+
+func TestSecretFlagWrappedUsage(t *testing.T) {
+	type testStruct struct {
+		Pass string `flag:"p" flagUsage:"pwd" flagSecret:"true"`
+	}
+	fls := Wrap(flag.NewFlagSet("app", flag.ContinueOnError))
+	require.NoError(t, fls.StructVar(&testStruct{Pass: "topsecret"}))
+	var parseErr error
+	output := captureOutput(fls, func() {
+		parseErr = fls.Parse([]string{"-x"})
+	})
+	require.Error(t, parseErr)
+	require.Equal(t, "flag provided but not defined: -x\nUsage of app:\n  -p string\n    \tpwd\n", output)
+	require.NotContains(t, output, "topsecret")
+}
+
+func TestResponseFileExpansion(t *testing.T) {
+	type testStruct struct {
+		Name  string   `flag:"name"`
+		Files []string `flagArgs:"true"`
+	}
+	path := filepath.Join(t.TempDir(), "args.txt")
+	require.NoError(t, os.WriteFile(path, []byte("-name 'John Doe'\nfile1"), 0600))
+
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"@" + path}))
+	require.Equal(t, testStruct{Files: []string{"@" + path}}, structVal)
+
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetResponseFileExpansion(true)
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"@" + path}))
+	require.Equal(t, testStruct{Name: "John Doe", Files: []string{"file1"}}, structVal)
+	require.NoError(t, fls.Parse([]string{"-name", "Jane", "--", "@" + path}))
+	require.Equal(t, testStruct{Name: "Jane", Files: []string{"@" + path}}, structVal)
+
+	var parseErr error
+	output := captureOutput(fls, func() {
+		parseErr = fls.Parse([]string{"@" + path + ".missing"})
+	})
+	var responseFileErr *cmdargs.ResponseFileError
+	require.True(t, errors.As(parseErr, &responseFileErr))
+	require.True(t, strings.HasPrefix(output, parseErr.Error()+"\nUsage:\n"))
+}
+
+func TestFlagUnknownField(t *testing.T) {
+	type testStruct struct {
+		Str          string              `flag:"s"`
+		UnknownArgs  []string            `flagUnknown:"true"`
+		UnknownFlags map[string][]string `flagUnknown:"true"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.SetIgnoreUnknown(true)
+	fls.SetIgnoreUnknownAmbiguousAsBoolFlags(true)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"-s", "a", "--x=1", "-v", "-x=2", "-y=false", "rem"}))
+	require.Equal(t, testStruct{
+		Str:         "a",
+		UnknownArgs: []string{"--x=1", "-v", "-x=2", "-y=false"},
+		UnknownFlags: map[string][]string{
+			"x": {"1", "2"},
+			"v": {"true"},
+			"y": {"false"},
+		},
+	}, structVal)
+	require.Equal(t, []cmdargs.Entry{
+		cmdargs.NewFlagEntry("x", "1").WithInline(true).WithDoubleDashes(true),
+		cmdargs.NewBoolFlagEntry("v", ""),
+		cmdargs.NewFlagEntry("x", "2").WithInline(true),
+		cmdargs.NewFlagEntry("y", "false").WithInline(true),
+	}, fls.GetIgnoredEntries())
+
+	type invalidStruct struct {
+		Unknown map[string]string `flagUnknown:"true"`
+	}
+	require.ErrorContains(t,
+		NewFlagSet("", flag.ContinueOnError).StructVar(&invalidStruct{}),
+		"[]string or map[string][]string expected",
+	)
+
+	type myStr string
+	type namedElemStruct struct {
+		Unknown []myStr `flagUnknown:"true"`
+	}
+	require.ErrorContains(t,
+		NewFlagSet("", flag.ContinueOnError).StructVar(&namedElemStruct{}),
+		"[]string or map[string][]string expected, got []flago.myStr",
+	)
+
+	type strs []string
+	type namedSliceStruct struct {
+		Unknown strs `flagUnknown:"true"`
+	}
+	namedSliceVal := namedSliceStruct{}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetIgnoreUnknown(true)
+	require.NoError(t, fls.StructVar(&namedSliceVal))
+	require.NoError(t, fls.Parse([]string{"--x=1"}))
+	require.Equal(t, strs{"--x=1"}, namedSliceVal.Unknown)
+}
+
+func TestUnknownFlagsManifest(t *testing.T) {
+	type testStruct struct {
+		Str   string   `flag:"s"`
+		Bool  bool     `flag:"b"`
+		Files []string `flagArgs:"true"`
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"v": true, "o": false, "s": true}`), 0600))
+	manifest, err := stdutil.LoadFormalTagNames(path)
+	require.NoError(t, err)
+
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.SetIgnoreUnknown(true)
+	fls.SetUnknownFlagsManifest(manifest)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"-v", "-o", "out", "-s", "str", "-u", "-b", "file"}))
+	require.Equal(t, testStruct{Str: "str", Files: []string{"file"}}, structVal)
+	require.Equal(t, []string{"-v", "-o", "out", "-u", "-b"}, fls.GetIgnoredArgs())
+
+	fls.SetIgnoreUnknownAmbiguousAsBoolFlags(true)
+	structVal = testStruct{}
+	require.NoError(t, fls.Parse([]string{"-v", "-o", "out", "-u", "-b", "file"}))
+	require.Equal(t, []string{"-v", "-o", "out", "-u"}, fls.GetIgnoredArgs())
+	require.True(t, structVal.Bool)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"v": "yes"}`), 0600))
+	_, err = stdutil.LoadFormalTagNames(path)
+	require.ErrorContains(t, err, path+": ")
+}
+
+func TestUnknownFlagsManifestPrefixMatching(t *testing.T) {
+	type testStruct struct {
+		Verbose bool `flag:"verbose"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.SetIgnoreUnknown(true)
+	fls.SetPrefixMatching(true)
+	fls.SetUnknownFlagsManifest(stdutil.FormalTagNames{"network": false})
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"--net", "host", "--verb"}))
+	require.True(t, structVal.Verbose)
+	// prefixes are resolved only to the registered flags
+	require.Equal(t, []string{"--net", "host"}, fls.GetIgnoredArgs())
+	require.Len(t, fls.GetIgnoredEntries(), 1)
+	require.Equal(t, "net", fls.GetIgnoredEntries()[0].(cmdargs.FlagEntry).Name())
+}
+
+func TestArgsError(t *testing.T) {
+	type testStruct struct {
+		Count int    `flag:"count"`
+		Mode  string `flags:"mode,m" flagEnum:"fast,slow"`
+		Pass  string `flag:"pass" flagSecret:"true"`
+		Bool  bool   `flag:"b"`
+	}
+	parse := func(args ...string) *ArgsError {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		require.NoError(t, fls.StructVar(&testStruct{}))
+		var err error
+		captureOutput(fls, func() {
+			err = fls.Parse(args)
+		})
+		var argsErr *ArgsError
+		require.ErrorAs(t, err, &argsErr)
+		require.Equal(t, err.Error(), argsErr.Error())
+		return argsErr
+	}
+
+	require.Equal(t, "-b --count=abc\n           ^^^", parse("-b", "--count=abc").Render())
+	require.Equal(t, "-count 1 -x 2\n          ^", parse("-count", "1", "-x", "2").Render())
+	require.Equal(t, "-b -count\n          ^", parse("-b", "-count").Render())
+	require.Equal(t, "-b=abc\n   ^^^", parse("-b=abc").Render())
+
+	argsErr := parse("-m", "fast", "-mode", "slow")
+	require.ErrorIs(t, argsErr, ErrMultipleAliases)
+	require.Equal(t, "-m fast -mode slow\n         ^^^^", argsErr.Render())
+
+	argsErr = parse("-mode", "fast", "-mode=other")
+	require.ErrorIs(t, argsErr, ErrNotAllowedValue)
+	require.Equal(t, "-mode fast -mode=other\n                 ^^^^^", argsErr.Render())
+
+	argsErr = parse("-pass", "secret", "-count", "x")
+	require.Equal(t, "-pass '******' -count x\n                      ^", argsErr.Render())
+}
+
+func TestArgsErrorOriginalArgs(t *testing.T) {
+	type testStruct struct {
+		Count int    `flags:"count,n"`
+		Pass  string `flag:"pass" flagSecret:"true"`
+		Bool  bool   `flag:"b"`
+	}
+	path := filepath.Join(t.TempDir(), "args.txt")
+	require.NoError(t, os.WriteFile(path, []byte("-pass secret2\n-count abc"), 0600))
+	parse := func(args ...string) *ArgsError {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		fls.SetIgnoreUnknown(true)
+		fls.SetResponseFileExpansion(true)
+		require.NoError(t, fls.StructVar(&testStruct{}))
+		var err error
+		captureOutput(fls, func() {
+			err = fls.Parse(args)
+		})
+		var argsErr *ArgsError
+		require.ErrorAs(t, err, &argsErr)
+		return argsErr
+	}
+
+	argsErr := parse("-u=1", "-v=2", "-n", "abc")
+	var invalidValueErr *InvalidValueError
+	require.ErrorAs(t, argsErr, &invalidValueErr)
+	require.Equal(t, 3, invalidValueErr.ArgIndex)
+	require.Equal(t, "-u=1 -v=2 -n abc\n             ^^^", argsErr.Render())
+
+	argsErr = parse("-u=1", "-pass", "secret", "-v=2", "-b", "@"+path)
+	require.ErrorAs(t, argsErr, &invalidValueErr)
+	require.Equal(t, 5, invalidValueErr.ArgIndex)
+	require.Equal(t, []string{"-u=1", "-pass", cmdargs.RedactedValue, "-v=2", "-b", "@" + path}, argsErr.Args.Args)
+	require.Equal(t, cmdargs.Span{Index: 5}, argsErr.Span)
+
+	argsErr = parse("-b", "-x=1", "-count")
+	var missingValueErr *MissingValueError
+	require.ErrorAs(t, argsErr, &missingValueErr)
+	require.Equal(t, 2, missingValueErr.ArgIndex)
+	require.Equal(t, "-b -x=1 -count\n               ^", argsErr.Render())
+}
+
+func TestParseErrorTypes(t *testing.T) {
+	type testStruct struct {
+		Count int    `flag:"count"`
+		Mode  string `flags:"mode,m"`
+		Pass  int    `flag:"pass" flagSecret:"true"`
+		Bool  bool   `flag:"b"`
+		Req   string `flags:"req,r" flagRequired:"true"`
+	}
+	parse := func(args ...string) error {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		require.NoError(t, fls.StructVar(&testStruct{}))
+		var err error
+		captureOutput(fls, func() {
+			err = fls.Parse(append([]string{"-r", "1"}, args...))
+		})
+		return err
+	}
+
+	var unknownFlagErr *UnknownFlagError
+	require.ErrorAs(t, parse("-count", "1", "--x=2"), &unknownFlagErr)
+	require.Equal(t, UnknownFlagError{Flag: "x", ArgIndex: 4}, *unknownFlagErr)
+
+	var invalidValueErr *InvalidValueError
+	require.ErrorAs(t, parse("-count", "abc"), &invalidValueErr)
+	require.Equal(t, "count", invalidValueErr.Flag)
+	require.Equal(t, "abc", invalidValueErr.Value)
+	require.Equal(t, 3, invalidValueErr.ArgIndex)
+	require.False(t, invalidValueErr.IsBool)
+	require.EqualError(t, invalidValueErr.Err, "parse error")
+
+	require.ErrorAs(t, parse("-b=yes"), &invalidValueErr)
+	require.Equal(t, InvalidValueError{Flag: "b", Value: "yes", Err: invalidValueErr.Err, ArgIndex: 2, IsBool: true},
+		*invalidValueErr)
+	require.EqualError(t, invalidValueErr, `invalid boolean value "yes" for -b: parse error`)
+
+	require.ErrorAs(t, parse("-pass=abc"), &invalidValueErr)
+	require.Equal(t, cmdargs.RedactedValue, invalidValueErr.Value)
+
+	var missingValueErr *MissingValueError
+	require.ErrorAs(t, parse("-b", "-mode"), &missingValueErr)
+	require.Equal(t, MissingValueError{Flag: "mode", ArgIndex: 3}, *missingValueErr)
+
+	var multipleAliasesErr *MultipleAliasesError
+	err := parse("-m", "a", "-mode", "b")
+	require.ErrorIs(t, err, ErrMultipleAliases)
+	require.ErrorAs(t, err, &multipleAliasesErr)
+	require.Equal(t, MultipleAliasesError{First: "mode", Second: "m"}, *multipleAliasesErr)
+
+	fls := NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	captureOutput(fls, func() {
+		err = fls.Parse(nil)
+	})
+	var requiredFlagErr *RequiredFlagError
+	require.ErrorIs(t, err, ErrIsRequired)
+	require.ErrorAs(t, err, &requiredFlagErr)
+	require.Equal(t, []string{"req", "r"}, requiredFlagErr.Names)
+	require.EqualError(t, err, `flag is required: "req"/"r"`)
+}
+
+func TestParseStdCompatibility(t *testing.T) {
+	argsCases := [][]string{
+		{"-s", "a", "--i=1", "pos", "-b"},
+		{"-b=false", "-s=", "--", "-i", "1"},
+		{"-s", "-b", "-i", "2", "-", "x"},
+		{"-s"},
+		{"-i", "x"},
+		{"-b=x"},
+		{"---b"},
+		{"-=1"},
+		{"-x", "1"},
+		{"-s", "a", "-help"},
+		{"-h"},
+		{"-b="},
+		{"-i=", "1"},
+		{"--=1"},
+		{"-i", "1", "-i", "x", "-s", "a"},
+	}
+	for _, args := range argsCases {
+		stdFls := flag.NewFlagSet("", flag.ContinueOnError)
+		stdFls.SetOutput(io.Discard)
+		stdS, stdI, stdB := stdFls.String("s", "", ""), stdFls.Int("i", 0, ""), stdFls.Bool("b", false, "")
+		stdErr := stdFls.Parse(args)
+
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		fls.SetOutput(io.Discard)
+		s, i, b := fls.String("s", "", ""), fls.Int("i", 0, ""), fls.Bool("b", false, "")
+		err := fls.Parse(args)
+
+		if stdErr == nil {
+			require.NoError(t, err, args)
+			require.Equal(t, stdFls.Args(), fls.Args(), args)
+		} else {
+			require.EqualError(t, err, stdErr.Error(), args)
+		}
+		require.Equal(t, []any{*stdS, *stdI, *stdB}, []any{*s, *i, *b}, args)
+	}
+}
+
+func TestParseErrorOutput(t *testing.T) {
+	newFlagSet := func() (*FlagSet, *int) {
+		fls := Wrap(flag.NewFlagSet("", flag.ContinueOnError))
+		fls.Int("i", 0, "")
+		usageCalls := 0
+		fls.Usage = func() {
+			usageCalls++
+		}
+		return fls, &usageCalls
+	}
+
+	fls, usageCalls := newFlagSet()
+	output := captureOutput(fls, func() {
+		require.Error(t, fls.Parse([]string{"-i", "x"}))
+	})
+	require.Equal(t, "invalid value \"x\" for flag -i: parse error\n", output)
+	require.Equal(t, 1, *usageCalls)
+
+	fls, usageCalls = newFlagSet()
+	output = captureOutput(fls, func() {
+		require.ErrorIs(t, fls.Parse([]string{"-h"}), flag.ErrHelp)
+	})
+	require.Empty(t, output)
+	require.Equal(t, 1, *usageCalls)
+	require.Equal(t, flag.ContinueOnError, fls.ErrorHandling())
+}
+
+func TestPrefixMatching(t *testing.T) {
+	type testStruct struct {
+		Verbose  bool   `flag:"verbose"`
+		Verbatim string `flag:"verbatim"`
+		Name     string `flags:"name,n"`
+		Host     string `flag:"host"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	var err error
+	captureOutput(fls, func() {
+		err = fls.Parse([]string{"--verbo"})
+	})
+	var unknownFlagErr *UnknownFlagError
+	require.ErrorAs(t, err, &unknownFlagErr)
+
+	fls.SetPrefixMatching(true)
+	require.NoError(t, fls.Parse([]string{"--verbo", "-verba=v", "-n", "x", "--ho", "h", "pos"}))
+	require.Equal(t, testStruct{Verbose: true, Verbatim: "v", Name: "x", Host: "h"}, structVal)
+	require.Equal(t, []string{"pos"}, fls.Args())
+
+	captureOutput(fls, func() {
+		err = fls.Parse([]string{"-n", "x", "--verb", "1"})
+	})
+	var ambiguousFlagErr *AmbiguousFlagError
+	require.ErrorAs(t, err, &ambiguousFlagErr)
+	require.Equal(t, AmbiguousFlagError{Flag: "verb", Candidates: []string{"verbatim", "verbose"}, ArgIndex: 2},
+		*ambiguousFlagErr)
+	require.EqualError(t, err, "ambiguous flag -verb: could be -verbatim or -verbose")
+	var argsErr *ArgsError
+	require.ErrorAs(t, err, &argsErr)
+	require.Equal(t, "-n x --verb 1\n       ^^^^", argsErr.Render())
+
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetPrefixMatching(true)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	captureOutput(fls, func() {
+		err = fls.Parse([]string{"-n", "x", "-nam", "y"})
+	})
+	require.ErrorIs(t, err, ErrMultipleAliases)
+	require.ErrorAs(t, err, &argsErr)
+	require.Equal(t, "-n x -nam y\n      ^^^", argsErr.Render())
+
+	captureOutput(fls, func() {
+		err = fls.Parse([]string{"-h"})
+	})
+	require.ErrorIs(t, err, flag.ErrHelp)
+
+	type aliasesStruct struct {
+		Verbose bool `flags:"verbose,verbosity"`
+	}
+	aliasesVal := aliasesStruct{}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetPrefixMatching(true)
+	require.NoError(t, fls.StructVar(&aliasesVal))
+	require.NoError(t, fls.Parse([]string{"--verbo"}))
+	require.True(t, aliasesVal.Verbose)
+
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetPrefixMatching(true)
+	fls.SetIgnoreUnknown(true)
+	structVal = testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{"--verb", "1", "-hos=a", "-x"}))
+	require.Equal(t, []string{"--verb", "1", "-x"}, fls.GetIgnoredArgs())
+	require.Equal(t, testStruct{Host: "a"}, structVal)
+}
+
+func TestNameNormalizer(t *testing.T) {
+	type testStruct struct {
+		LogLevel string `flags:"log-level,ll"`
+		Debug    bool   `flag:"debug"`
+	}
+	newFlagSet := func(structVal *testStruct) *FlagSet {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		fls.SetNameNormalizer(cmdargs.FoldCaseAndSeparators)
+		require.NoError(t, fls.StructVar(structVal))
+		return fls
+	}
+	structVal := testStruct{}
+	fls := newFlagSet(&structVal)
+	require.NoError(t, fls.Parse([]string{"--Log_Level", "info", "-DEBUG"}))
+	require.Equal(t, testStruct{LogLevel: "info", Debug: true}, structVal)
+	require.Equal(t, "Usage:\n  -debug\n    \t\n  -ll -log-level string\n    \t\n", captureOutput(fls, fls.Usage))
+
+	fls = newFlagSet(&testStruct{})
+	var err error
+	captureOutput(fls, func() {
+		err = fls.Parse([]string{"-LL", "a", "--log_level", "b"})
+	})
+	require.ErrorIs(t, err, ErrMultipleAliases)
+	var argsErr *ArgsError
+	require.ErrorAs(t, err, &argsErr)
+	require.Equal(t, "-LL a --log_level b\n        ^^^^^^^^^", argsErr.Render())
+
+	structVal = testStruct{}
+	fls = newFlagSet(&structVal)
+	fls.SetIgnoreUnknown(true)
+	require.NoError(t, fls.Parse([]string{"-Debug", "--x", "1", "-LOG-LEVEL=warn"}))
+	require.Equal(t, testStruct{LogLevel: "warn", Debug: true}, structVal)
+	require.Equal(t, []string{"--x", "1"}, fls.GetIgnoredArgs())
+}
@@ -0,0 +1,130 @@
+package flago
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// INISyntaxError is returned if an INI file has invalid syntax
+type INISyntaxError struct {
+	FileName string
+	Line     int
+	Message  string
+}
+
+func (e *INISyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.FileName, e.Line, e.Message)
+}
+
+// INIValues contains values read from an INI file.
+// Keys are section names ("" for the keys before the first section), values are maps of keys
+// to all values of the repeated keys in order of appearance
+type INIValues map[string]map[string][]string
+
+// ReadINIFile reads the INI file. Supported syntax:
+// - `[section]` lines start a section
+// - `key = value` lines. Values can be enclosed in double or single quotes that are removed
+// - lines starting with `;` or `#` are comments
+func ReadINIFile(path string) (INIValues, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseINI(content, path)
+}
+
+// AddINIFile reads the INI file (see ReadINIFile) and adds a Source (see AddSource) providing values
+// for the flags.
+// Keys in the `[section]` correspond to the fields of the nested struct with `flagPrefix` that has the section
+// name as a key (prefix without trailing separators, e.g. `flagPrefix:"sender-"` corresponds to `[sender]`).
+// Sections of deeper nested structs are named by dot-separated keys (`[sender.address]`). The flag prefix is
+// not included in the key names. Keys before the first section correspond to full flag names.
+// Repeated keys provide multiple values for slice fields
+func (fls *FlagSet) AddINIFile(path string, priority int) error {
+	values, err := ReadINIFile(path)
+	if err != nil {
+		return err
+	}
+	fls.AddSource(iniSource{fls: fls, values: values}, priority)
+	return nil
+}
+
+type iniSource struct {
+	fls    *FlagSet
+	values INIValues
+}
+
+func (s iniSource) Lookup(flagName string, fieldPath string) (string, bool, error) {
+	values, ok, err := s.LookupAll(flagName, fieldPath)
+	if !ok || err != nil {
+		return "", ok, err
+	}
+	return values[len(values)-1], true, nil
+}
+
+func (s iniSource) LookupAll(flagName string, _ string) ([]string, bool, error) {
+	if groups := s.fls.getFlagGroups(flagName); len(groups) > 0 {
+		keys := make([]string, len(groups))
+		for i, group := range groups {
+			keys[i] = group.key
+		}
+		key := strings.TrimPrefix(flagName, groups[len(groups)-1].flagPrefix)
+		if values, ok := s.values[strings.Join(keys, ".")][key]; ok {
+			return values, true, nil
+		}
+	}
+	values, ok := s.values[""][flagName]
+	return values, ok, nil
+}
+
+func parseINI(content []byte, fileName string) (INIValues, error) {
+	res := make(INIValues)
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		newSyntaxError := func(format string, a ...any) error {
+			return &INISyntaxError{
+				FileName: fileName,
+				Line:     lineNumber,
+				Message:  fmt.Sprintf(format, a...),
+			}
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, newSyntaxError(`expected "]" at the end of the section name`)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, value, hasValue := strings.Cut(line, "=")
+		if !hasValue {
+			return nil, newSyntaxError(`expected "key = value"`)
+		}
+		if key = strings.TrimSpace(key); key == "" {
+			return nil, newSyntaxError("empty key")
+		}
+		value = unquoteINIValue(strings.TrimSpace(value))
+		if res[section] == nil {
+			res[section] = make(map[string][]string)
+		}
+		res[section][key] = append(res[section][key], value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return res, nil
+}
+
+func unquoteINIValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+package flago
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseINI(t *testing.T) {
+	content := `
+; comment
+name = root
+# comment
+[sender]
+email = "a@b.c"
+tag = 1
+tag = '2'
+empty =
+[ sender.address ]
+city=Berlin
+`
+	values, err := parseINI([]byte(content), "test.ini")
+	require.NoError(t, err)
+	require.Equal(t, INIValues{
+		"":               {"name": {"root"}},
+		"sender":         {"email": {"a@b.c"}, "tag": {"1", "2"}, "empty": {""}},
+		"sender.address": {"city": {"Berlin"}},
+	}, values)
+}
+
+func TestParseINIErrors(t *testing.T) {
+	testCases := []struct {
+		content string
+		line    int
+		message string
+	}{
+		{"a=1\n[section", 2, `expected "]" at the end of the section name`},
+		{"a=1\n\nb", 3, `expected "key = value"`},
+		{" = 1", 1, "empty key"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.content, func(t *testing.T) {
+			_, err := parseINI([]byte(testCase.content), "test.ini")
+			var syntaxErr *INISyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			require.Equal(t, "test.ini", syntaxErr.FileName)
+			require.Equal(t, testCase.line, syntaxErr.Line)
+			require.Equal(t, testCase.message, syntaxErr.Message)
+		})
+	}
+}
+
+func TestAddINIFile(t *testing.T) {
+	type address struct {
+		City *string `flag:"city"`
+		Zip  string  `flag:"zip"`
+	}
+	type person struct {
+		Email   string   `flag:"email" flagRequired:"true"`
+		Tags    []string `flag:"tag"`
+		Address address  `flagPrefix:"address-"`
+	}
+	type testStruct struct {
+		Name     string  `flag:"name"`
+		Port     *int    `flag:"port"`
+		Sender   person  `flagPrefix:"sender-"`
+		Receiver person  `flagPrefix:"receiver-"`
+		Ratio    float64 `flag:"ratio"`
+	}
+	path := filepath.Join(t.TempDir(), "config.ini")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name = ini_name
+receiver-email = r@b.c
+ratio = 0.5
+[sender]
+email = s@b.c
+tag = a
+tag = b
+[sender.address]
+city = Berlin
+`), 0600))
+
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{
+		Sender: person{Tags: []string{"default"}},
+	}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.AddINIFile(path, 0))
+	require.NoError(t, fls.Parse([]string{"-name", "cmd_name"}))
+	require.Equal(t, testStruct{
+		Name: "cmd_name",
+		Sender: person{
+			Email: "s@b.c",
+			Tags:  []string{"a", "b"},
+			Address: address{
+				City: ptr("Berlin"),
+			},
+		},
+		Receiver: person{Email: "r@b.c"},
+		Ratio:    0.5,
+	}, structVal)
+
+	require.NoError(t, os.WriteFile(path, []byte("[sender]\nemail\n"), 0600))
+	require.EqualError(t, fls.AddINIFile(path, 0), path+`:2: expected "key = value"`)
+}
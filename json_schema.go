@@ -0,0 +1,223 @@
+package flago
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is a subset of JSON Schema keywords used to describe a FlagSet.
+// Keywords with "x-" prefix are custom extensions:
+// - "x-flag": the primary flag name
+// - "x-aliases": other (not deprecated) flag names assigned to the same field
+// - "x-flagPrefix": the resulting prefix of flag names in a nested struct
+// - "x-args": positional args that will be assigned to `flagArgs` fields
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Enum        []any                  `json:"enum,omitempty"`
+	Default     any                    `json:"default,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Flag        string                 `json:"x-flag,omitempty"`
+	Aliases     []string               `json:"x-aliases,omitempty"`
+	FlagPrefix  string                 `json:"x-flagPrefix,omitempty"`
+	Args        *jsonSchema            `json:"x-args,omitempty"`
+}
+
+func newObjectJSONSchema() *jsonSchema {
+	return &jsonSchema{
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema),
+	}
+}
+
+// WriteJSONSchema writes JSON Schema describing the registered flags to `w`.
+//
+// Each not hidden flag is described by a property with the primary flag name as a key. Nested structs
+// with non-empty `flagPrefix` are described by nested objects with the prefix (without trailing separators)
+// as a key, keys of their properties don't contain the prefix.
+// The schema contains types, allowed values (`flagEnum` tag), not zero default values, deprecation and
+// required status of the flags. Custom "x-flag", "x-aliases", "x-flagPrefix" keywords contain full
+// flag names and prefixes, "x-args" keyword describes positional args if `flagArgs` field is registered.
+// Returns an error if a flag and a nested struct have the same key
+func (fls *FlagSet) WriteJSONSchema(w io.Writer) error {
+	root := newObjectJSONSchema()
+	root.Schema = jsonSchemaDraft
+	root.Title = fls.Name()
+
+	indexedFlagNames := indexFormalFlagNames(fls)
+	structFlagNames := make(map[string]struct{})
+
+	for _, structFields := range fls.registeredFields {
+		for _, namedFlagsField := range structFields.namedFlagFields {
+			for _, namedFlagField := range namedFlagsField.fields {
+				structFlagNames[namedFlagField.flagName] = struct{}{}
+			}
+			role := namedFlagsField.info.namedFlagRole
+			fNames := indexedFlagNames[role.flagNames[0]]
+			if fNames == nil || fNames.isHidden {
+				continue
+			}
+			parent := root
+			for _, group := range namedFlagsField.info.groups {
+				var err error
+				if parent, err = getJSONSchemaGroup(parent, group); err != nil {
+					return err
+				}
+			}
+			usedFlagNames := role.getUsedFlagNames()
+			flagSchema := newFlagJSONSchema(fNames, usedFlagNames)
+			enumSchema := flagSchema
+			if flagSchema.Items != nil {
+				enumSchema = flagSchema.Items
+			}
+			for _, allowedValue := range role.enum {
+				enumSchema.Enum = append(enumSchema.Enum, getJSONSchemaValue(enumSchema.Type, allowedValue))
+			}
+			key := usedFlagNames[0]
+			if groups := namedFlagsField.info.groups; len(groups) > 0 {
+				key = strings.TrimPrefix(key, groups[len(groups)-1].flagPrefix)
+			}
+			if err := setJSONSchemaFlagProperty(parent, key, flagSchema); err != nil {
+				return err
+			}
+			if namedFlagsField.isRequired {
+				parent.Required = append(parent.Required, key)
+			}
+		}
+		if len(structFields.flagArgsToSet) > 0 {
+			root.Args = &jsonSchema{
+				Type:  "array",
+				Items: &jsonSchema{Type: "string"},
+			}
+		}
+	}
+
+	// flags registered in the wrapped FlagSet directly
+	var err error
+	fls.VisitAll(func(f *flag.Flag) {
+		if _, isStructFlag := structFlagNames[f.Name]; isStructFlag || err != nil {
+			return
+		}
+		if fNames := indexedFlagNames[f.Name]; !fNames.isHidden && fNames.names[0] == f.Name {
+			err = setJSONSchemaFlagProperty(root, f.Name, newFlagJSONSchema(fNames, fNames.names))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	sortJSONSchemaRequired(root)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(root)
+}
+
+// getJSONSchemaGroup returns the nested object describing the group adding it to `parent` if needed
+func getJSONSchemaGroup(parent *jsonSchema, group flagsGroup) (*jsonSchema, error) {
+	res, ok := parent.Properties[group.key]
+	if !ok {
+		res = newObjectJSONSchema()
+		res.FlagPrefix = group.flagPrefix
+		parent.Properties[group.key] = res
+	} else if res.FlagPrefix == "" {
+		return nil, newJSONKeyCollisionError(group.key, group.flagPrefix)
+	}
+	return res, nil
+}
+
+// setJSONSchemaFlagProperty adds the flag property to `parent` checking that the key is not used by a group
+func setJSONSchemaFlagProperty(parent *jsonSchema, key string, flagSchema *jsonSchema) error {
+	if existing, ok := parent.Properties[key]; ok && existing.FlagPrefix != "" {
+		return newJSONKeyCollisionError(key, existing.FlagPrefix)
+	}
+	parent.Properties[key] = flagSchema
+	return nil
+}
+
+func newJSONKeyCollisionError(key string, flagPrefix string) error {
+	return fmt.Errorf(`key "%s" is used by both a flag and the flags with "%s" prefix`, key, flagPrefix)
+}
+
+// newFlagJSONSchema creates JSON Schema of the flag. The first of `names` is the primary name, others are aliases
+func newFlagJSONSchema(fNames *flagNames, names []string) *jsonSchema {
+	_, usage := flag.UnquoteUsage(fNames.f)
+	res := &jsonSchema{
+		Description: strings.TrimSpace(usage),
+		Flag:        names[0],
+		Deprecated:  fNames.deprecation != "",
+	}
+	if len(names) > 1 {
+		res.Aliases = names[1:]
+	}
+	if sv, ok := unwrapFlagValue(fNames.f.Value).(*sliceValue); ok {
+		res.Type = "array"
+		res.Items = &jsonSchema{}
+		res.Items.Type, res.Items.Format = getFlagValueJSONType(sv.elemFlag.Value)
+		return res
+	}
+	res.Type, res.Format = getFlagValueJSONType(fNames.f.Value)
+	if !fNames.isSecret && !isZeroDefaultValue(fNames.f) {
+		res.Default = getJSONSchemaValue(res.Type, fNames.f.DefValue)
+	}
+	return res
+}
+
+// getFlagValueJSONType returns JSON Schema type and format of the values of the flag
+func getFlagValueJSONType(value flag.Value) (jsonType string, format string) {
+	if isBoolFlagValue(value) {
+		return "boolean", ""
+	}
+	valueType := reflect.TypeOf(unwrapFlagValue(value))
+	if valueType.Kind() != reflect.Pointer || valueType.Elem().PkgPath() != "flag" {
+		return "string", ""
+	}
+	if valueType.Elem().Name() == "durationValue" {
+		return "string", "duration"
+	}
+	switch valueType.Elem().Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return "integer", ""
+	case reflect.Float64:
+		return "number", ""
+	default:
+		return "string", ""
+	}
+}
+
+// getJSONSchemaValue converts the string flag value to the value of the given JSON Schema type
+func getJSONSchemaValue(jsonType string, value string) any {
+	switch jsonType {
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	}
+	return value
+}
+
+func sortJSONSchemaRequired(schema *jsonSchema) {
+	sort.Strings(schema.Required)
+	for _, property := range schema.Properties {
+		sortJSONSchemaRequired(property)
+	}
+}
@@ -0,0 +1,101 @@
+package flago
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONSchema(t *testing.T) {
+	type personFlags struct {
+		Name  string `flag:"name" flagUsage:"person name" flagRequired:"true"`
+		Email string `flag:"email"`
+	}
+	type testStruct struct {
+		Verbose  int           `flags:"verbose,v,vv" flagUsage:"verbose mode" flagDeprecatedAliases:"vv"`
+		Format   string        `flag:"format" flagEnum:"json,text"`
+		Login    *string       `flag:"login" flagUsage:"user login" flagRequired:"true"`
+		Timeout  time.Duration `flag:"timeout"`
+		Ratio    float64       `flag:"ratio"`
+		Old      bool          `flag:"old" flagDeprecated:"use -verbose"`
+		Secret   string        `flag:"hidden" flagHidden:"true"`
+		Sender   personFlags   `flagPrefix:"sender-"`
+		Receiver personFlags   `flagPrefix:"r." flagUsagePrefix:"receiver "`
+		Common   personFlags   `flagPrefix:""`
+		Files    []string      `flagArgs:"true"`
+	}
+	fls := NewFlagSet("myApp", flag.ContinueOnError)
+	fls.Uint("std", 3, "std flag")
+	structVal := testStruct{
+		Format:  "text",
+		Timeout: time.Second,
+		Sender: personFlags{
+			Name: "John",
+		},
+	}
+	require.NoError(t, fls.StructVar(&structVal))
+
+	buf := bytes.Buffer{}
+	require.NoError(t, fls.WriteJSONSchema(&buf))
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "myApp",
+		"type": "object",
+		"properties": {
+			"verbose": {"type": "integer", "description": "verbose mode", "x-flag": "verbose", "x-aliases": ["v"]},
+			"format": {"type": "string", "enum": ["json", "text"], "default": "text", "x-flag": "format"},
+			"login": {"type": "string", "description": "user login", "x-flag": "login"},
+			"timeout": {"type": "string", "format": "duration", "default": "1s", "x-flag": "timeout"},
+			"ratio": {"type": "number", "x-flag": "ratio"},
+			"old": {"type": "boolean", "deprecated": true, "x-flag": "old"},
+			"std": {"type": "integer", "description": "std flag", "default": 3, "x-flag": "std"},
+			"name": {"type": "string", "description": "person name", "x-flag": "name"},
+			"email": {"type": "string", "x-flag": "email"},
+			"sender": {
+				"type": "object",
+				"x-flagPrefix": "sender-",
+				"properties": {
+					"name": {"type": "string", "description": "person name", "default": "John", "x-flag": "sender-name"},
+					"email": {"type": "string", "x-flag": "sender-email"}
+				}
+			},
+			"r": {
+				"type": "object",
+				"x-flagPrefix": "r.",
+				"properties": {
+					"name": {"type": "string", "description": "receiver person name", "x-flag": "r.name"},
+					"email": {"type": "string", "description": "receiver", "x-flag": "r.email"}
+				},
+				"required": ["name"]
+			}
+		},
+		"required": ["login", "name"],
+		"x-args": {"type": "array", "items": {"type": "string"}}
+	}`, buf.String())
+}
+
+func TestWriteJSONSchemaKeyCollision(t *testing.T) {
+	type tlsFlags struct {
+		Cert string `flag:"cert"`
+	}
+	type testStruct struct {
+		TLS     bool     `flag:"tls"`
+		TLSOpts tlsFlags `flagPrefix:"tls-"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	require.EqualError(t, fls.WriteJSONSchema(&bytes.Buffer{}),
+		`key "tls" is used by both a flag and the flags with "tls-" prefix`)
+
+	type groupStruct struct {
+		TLSOpts tlsFlags `flagPrefix:"tls-"`
+	}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.Bool("tls", false, "")
+	require.NoError(t, fls.StructVar(&groupStruct{}))
+	require.EqualError(t, fls.WriteJSONSchema(&bytes.Buffer{}),
+		`key "tls" is used by both a flag and the flags with "tls-" prefix`)
+}
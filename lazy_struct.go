@@ -0,0 +1,94 @@
+package flago
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// lazyStruct describes a nil pointer to a struct containing flags. The flags are registered in a new struct
+// that is assigned to the pointer only if any of them is parsed
+type lazyStruct struct {
+	// pointerValue is the settable nil pointer field
+	pointerValue reflect.Value
+	// structPtr points to the new struct containing the registered fields
+	structPtr reflect.Value
+	// parent is the lazy struct containing the pointer field. Nil if there is no such struct
+	parent *lazyStruct
+}
+
+// isAssigned returns true if the pointer and the pointers of all lazy parents are assigned.
+// Nil lazyStruct is always assigned
+func (s *lazyStruct) isAssigned() bool {
+	for ; s != nil; s = s.parent {
+		if s.pointerValue.IsNil() {
+			return false
+		}
+	}
+	return true
+}
+
+// dereferenceStructPointer returns the struct the pointer field points to. If the pointer is nil,
+// returns a new struct that will be assigned to the field after Parse() only if any of its flags is parsed.
+// Returns an error if the struct type is already allocated by one of the lazy parents (recursive type)
+func (p *parentStructInfo) dereferenceStructPointer(pointerValue reflect.Value) (reflect.Value, error) {
+	pointerValue = getAccessibleValue(pointerValue)
+	if !pointerValue.IsNil() {
+		return pointerValue.Elem(), nil
+	}
+	structType := pointerValue.Type().Elem()
+	for s := p.lazyStruct; s != nil; s = s.parent {
+		if s.structPtr.Type().Elem() == structType {
+			return reflect.Value{}, fmt.Errorf("recursive nil pointer to %s", structType.String())
+		}
+	}
+	p.lazyStruct = &lazyStruct{
+		pointerValue: pointerValue,
+		structPtr:    reflect.New(structType),
+		parent:       p.lazyStruct,
+	}
+	return p.lazyStruct.structPtr.Elem(), nil
+}
+
+// getUsedLazyStructs returns the lazy structs containing at least one parsed flag, non-empty `flagArgs` or
+// `flagUnknown` field (directly or in the nested lazy structs)
+func (fls *FlagSet) getUsedLazyStructs(existingFlagNames map[string]struct{}) map[*lazyStruct]struct{} {
+	res := make(map[*lazyStruct]struct{})
+	markUsed := func(s *lazyStruct) {
+		for ; s != nil; s = s.parent {
+			res[s] = struct{}{}
+		}
+	}
+	for _, structFields := range fls.registeredFields {
+		for _, namedFlagsField := range structFields.namedFlagFields {
+			if namedFlagsField.info.lazyStruct == nil {
+				continue
+			}
+			for _, namedFlagField := range namedFlagsField.fields {
+				if _, exists := existingFlagNames[namedFlagField.flagName]; exists {
+					markUsed(namedFlagsField.info.lazyStruct)
+					break
+				}
+			}
+		}
+		if fls.FlagSet.NArg() > 0 {
+			for _, info := range structFields.flagArgsToSet {
+				markUsed(info.lazyStruct)
+			}
+		}
+		if len(fls.ignoredEntries) > 0 {
+			for _, info := range structFields.flagUnknownToSet {
+				markUsed(info.lazyStruct)
+			}
+		}
+	}
+	return res
+}
+
+// isLazyStructUsed returns true if `s` is nil or is in `usedLazyStructs`
+func isLazyStructUsed(s *lazyStruct, usedLazyStructs map[*lazyStruct]struct{}) bool {
+	if s == nil {
+		return true
+	}
+	_, isUsed := usedLazyStructs[s]
+	return isUsed
+}
@@ -0,0 +1,93 @@
+package flago
+
+import (
+	"encoding"
+	"flag"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives a flag name from a field that has no `flag`, `flags` or other behavior tags
+// (see SetNamingStrategy). `fieldPath` contains Go names of the nested structs without `flagPrefix` tags
+// (from outer to inner) followed by the name of the field, e.g. ["Server", "MaxConns"]
+type NamingStrategy func(fieldPath []string) string
+
+// KebabCase is NamingStrategy producing "server-max-conns" names
+func KebabCase(fieldPath []string) string {
+	return strings.Join(getLowerWords(fieldPath), "-")
+}
+
+// SnakeCase is NamingStrategy producing "server_max_conns" names
+func SnakeCase(fieldPath []string) string {
+	return strings.Join(getLowerWords(fieldPath), "_")
+}
+
+// CamelCase is NamingStrategy producing "serverMaxConns" names
+func CamelCase(fieldPath []string) string {
+	words := getLowerWords(fieldPath)
+	for i := 1; i < len(words); i++ {
+		runes := []rune(words[i])
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, "")
+}
+
+func getLowerWords(fieldPath []string) []string {
+	var res []string
+	for _, name := range fieldPath {
+		for _, word := range splitGoName(name) {
+			res = append(res, strings.ToLower(word))
+		}
+	}
+	return res
+}
+
+// splitGoName splits a Go identifier into words: "HTTPServerURL2" -> ["HTTP", "Server", "URL2"]
+func splitGoName(name string) (res []string) {
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		isNextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if cur == '_' {
+			if start < i {
+				res = append(res, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if unicode.IsUpper(cur) && prev != '_' &&
+			(unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && isNextLower)) {
+			if start < i {
+				res = append(res, string(runes[start:i]))
+			}
+			start = i
+		}
+	}
+	if start < len(runes) {
+		res = append(res, string(runes[start:]))
+	}
+	return res
+}
+
+// isAutoNestedStructType returns true if a field of the type without tags should be treated as a nested
+// struct (not as a flag) if the naming strategy is set. Structs without exported fields and structs
+// that can be set from a string (flag.Value, encoding.TextUnmarshaler, e.g. time.Time) are treated as flags
+func isAutoNestedStructType(fieldType reflect.Type) bool {
+	if fieldType.Kind() != reflect.Struct {
+		return false
+	}
+	ptrType := reflect.PointerTo(fieldType)
+	if ptrType.Implements(reflect.TypeOf((*flag.Value)(nil)).Elem()) ||
+		ptrType.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+		return false
+	}
+	for i := 0; i < fieldType.NumField(); i++ {
+		if fieldType.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package flago
+
+import (
+	"flag"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	for _, tc := range []struct {
+		fieldPath []string
+		kebab     string
+		snake     string
+		camel     string
+	}{
+		{[]string{"Port"}, "port", "port", "port"},
+		{[]string{"MaxConns"}, "max-conns", "max_conns", "maxConns"},
+		{[]string{"HTTPServer", "URL"}, "http-server-url", "http_server_url", "httpServerUrl"},
+		{[]string{"UserID2", "S3Bucket"}, "user-id2-s3-bucket", "user_id2_s3_bucket", "userId2S3Bucket"},
+		{[]string{"Log_Level"}, "log-level", "log_level", "logLevel"},
+	} {
+		require.Equal(t, tc.kebab, KebabCase(tc.fieldPath), tc.fieldPath)
+		require.Equal(t, tc.snake, SnakeCase(tc.fieldPath), tc.fieldPath)
+		require.Equal(t, tc.camel, CamelCase(tc.fieldPath), tc.fieldPath)
+	}
+}
+
+func TestStructVarNamingStrategy(t *testing.T) {
+	type server struct {
+		MaxConns int
+		Timeout  time.Duration `flagUsage:"timeout"`
+	}
+	type sender struct {
+		Addr string
+	}
+	type testStruct struct {
+		LogLevel string
+		Verbose  bool   `flags:"verbose,v"`
+		Skipped  string `flag:"-"`
+		Server   server
+		Sender   sender   `flagPrefix:"snd."`
+		Files    []string `flagArgs:"true"`
+		internal string
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(KebabCase)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	require.NoError(t, fls.Parse([]string{
+		"-log-level", "info", "-v", "-server-max-conns", "5", "-server-timeout", "1s", "-snd.addr", "a", "file",
+	}))
+	require.Equal(t, testStruct{
+		LogLevel: "info",
+		Verbose:  true,
+		Server:   server{MaxConns: 5, Timeout: time.Second},
+		Sender:   sender{Addr: "a"},
+		Files:    []string{"file"},
+	}, structVal)
+	require.Nil(t, fls.Lookup("skipped"))
+	require.Nil(t, fls.Lookup("internal"))
+	require.Equal(t, "timeout", fls.Lookup("server-timeout").Usage)
+
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(CamelCase)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	require.NotNil(t, fls.Lookup("serverMaxConns"))
+
+	require.NoError(t, NewFlagSet("", flag.ContinueOnError).StructVar(&testStruct{}))
+
+	type invalidStruct struct {
+		Callbacks map[string]string
+	}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(SnakeCase)
+	require.ErrorContains(t, fls.StructVar(&invalidStruct{}),
+		`field "Callbacks" named by the naming strategy: unsupported field type`)
+
+	// structs set from a string and structs without exported fields are not nested
+	type textStruct struct {
+		Since time.Time
+	}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(KebabCase)
+	require.EqualError(t, fls.StructVar(&textStruct{}),
+		`field "Since" named by the naming strategy: unsupported field type Time (use flag:"-" tag to skip it)`)
+
+	type unexportedStruct struct {
+		Lock struct{ locked bool }
+	}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(KebabCase)
+	err := fls.StructVar(&unexportedStruct{})
+	require.ErrorContains(t, err, `field "Lock" named by the naming strategy: `)
+	require.ErrorContains(t, err, `(use flag:"-" tag to skip it)`)
+
+	type urlStruct struct {
+		Endpoint url.URL
+	}
+	fls = NewFlagSet("", flag.ContinueOnError)
+	fls.SetNamingStrategy(KebabCase)
+	err = fls.StructVar(&urlStruct{})
+	require.ErrorContains(t, err, `field "Endpoint" nested by the naming strategy: field "Endpoint.User" named `+
+		`by the naming strategy: `)
+	require.Equal(t, 1, strings.Count(err.Error(), `(use flag:"-" tag to skip it)`))
+	require.True(t, strings.HasSuffix(err.Error(), `(use flag:"-" tag to skip it)`))
+}
@@ -0,0 +1,184 @@
+package flago
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/cardinalby/go-struct-flags/cmdargs"
+)
+
+// validatedFlag is a flag found in the args by validateFlagArgs
+type validatedFlag struct {
+	name string
+	// value is the passed value ("true" for bool flags without value) or cmdargs.RedactedValue
+	// if the flag is secret
+	value     string
+	isBool    bool
+	valueSpan cmdargs.Span
+}
+
+// validateFlagArgs checks the flags in fls.parsedArgs using the cmdargs tokenizer before they are
+// parsed by the wrapped FlagSet. It returns typed errors (UnknownFlagError, AmbiguousFlagError, MissingValueError)
+// wrapped in ArgsError for the errors that Parse() of the wrapped FlagSet would return.
+// Returns `arguments` with the flag names resolved by prefix matching and the name normalizer and
+// the flags in the order they will be set by Parse() of the wrapped FlagSet. Validation stops at the
+// first non-flag arg, "--" or a help flag
+func (fls *FlagSet) validateFlagArgs(arguments []string) (resolvedArgs []string, flags []validatedFlag, err error) {
+	resolvedArgs = arguments
+	isResolvedArgsCopied := false
+	var flagNameToken *cmdargs.Token
+	fls.parsedArgs.IterateTokens(func(token cmdargs.Token) bool {
+		switch {
+		case token.Role.Has(cmdargs.RoleFlagValue):
+			flags = append(flags, validatedFlag{
+				name:      flagNameToken.FlagName,
+				value:     token.FlagValue,
+				valueSpan: cmdargs.Span{Index: token.Index},
+			})
+			flagNameToken = nil
+			return true
+		case token.Role.Has(cmdargs.RoleUnnamed):
+			// the tokenizer doesn't treat "-=x" as a flag, but the wrapped FlagSet does
+			if len(token.Arg) > 1 && token.Arg[0] == '-' {
+				err = fls.newArgsError(fmt.Errorf("bad flag syntax: %s", token.Arg), cmdargs.Span{Index: token.Index})
+			}
+			return false
+		case !token.Role.Has(cmdargs.RoleFlag):
+			return false
+		}
+
+		passedName := token.FlagName
+		if token.PassedFlagName != "" {
+			passedName = token.PassedFlagName
+		}
+		nameSpan := cmdargs.Span{
+			Index: token.Index,
+			Start: token.FlagNameOffset,
+			End:   token.FlagNameOffset + len(passedName),
+		}
+		if passedName[0] == '-' {
+			err = fls.newArgsError(fmt.Errorf("bad flag syntax: %s", token.Arg), cmdargs.Span{Index: token.Index})
+			return false
+		}
+		if (passedName == "help" || passedName == "h") && fls.FlagSet.Lookup(passedName) == nil {
+			// the wrapped FlagSet returns flag.ErrHelp even if the name can be resolved by prefix matching
+			return false
+		}
+		if !token.Role.Has(cmdargs.RoleKnown) {
+			if _, candidates, _ := fls.parsedArgs.ResolveFlagName(passedName); len(candidates) > 0 {
+				err = fls.newArgsError(
+					&AmbiguousFlagError{Flag: passedName, Candidates: candidates, ArgIndex: fls.originalArgIndex(token.Index)},
+					nameSpan,
+				)
+				return false
+			}
+			unknownFlagErr := &UnknownFlagError{Flag: passedName, ArgIndex: fls.originalArgIndex(token.Index)}
+			if fls.unknownFlagSuggestions {
+				unknownFlagErr.Suggestions = fls.getUnknownFlagSuggestions(passedName)
+			}
+			err = fls.newArgsError(unknownFlagErr, nameSpan)
+			return false
+		}
+
+		if token.PassedFlagName != "" {
+			if !isResolvedArgsCopied {
+				resolvedArgs, isResolvedArgsCopied = append([]string(nil), arguments...), true
+			}
+			arg := arguments[token.Index]
+			resolvedArgs[token.Index] = arg[:nameSpan.Start] + token.FlagName + arg[nameSpan.End:]
+		}
+		isBool := token.Role.Has(cmdargs.RoleBoolFlag)
+		switch {
+		case token.Role.Has(cmdargs.RoleInline):
+			flags = append(flags, validatedFlag{
+				name:      token.FlagName,
+				value:     token.FlagValue,
+				isBool:    isBool,
+				valueSpan: cmdargs.Span{Index: token.Index, Start: token.FlagValueOffset, End: len(token.Arg)},
+			})
+		case isBool:
+			flags = append(flags, validatedFlag{name: token.FlagName, value: "true", isBool: true, valueSpan: nameSpan})
+		default:
+			flagNameToken = &token
+		}
+		return true
+	})
+	if err == nil && flagNameToken != nil {
+		err = fls.newArgsError(
+			&MissingValueError{Flag: flagNameToken.FlagName, ArgIndex: fls.originalArgIndex(flagNameToken.Index)},
+			cmdargs.Span{Index: len(arguments)},
+		)
+	}
+	return resolvedArgs, flags, err
+}
+
+// parseWrappedFlagSet calls Parse() of the wrapped FlagSet with the args validated by validateFlagArgs.
+// An error returned by flag.Value.Set() is converted to InvalidValueError wrapped in ArgsError.
+// The wrapped FlagSet doesn't print errors and usage, the caller should handle the returned error
+// (including flag.ErrHelp)
+func (fls *FlagSet) parseWrappedFlagSet(resolvedArgs []string, flags []validatedFlag) error {
+	errorHandling, output, usage := fls.FlagSet.ErrorHandling(), fls.FlagSet.Output(), fls.FlagSet.Usage
+	fls.FlagSet.Init(fls.FlagSet.Name(), flag.ContinueOnError)
+	fls.FlagSet.SetOutput(io.Discard)
+	fls.FlagSet.Usage = func() {}
+	defer func() {
+		fls.FlagSet.Init(fls.FlagSet.Name(), errorHandling)
+		fls.FlagSet.SetOutput(output)
+		fls.FlagSet.Usage = usage
+	}()
+
+	var setErr error
+	setCallsCount := 0
+	onSet := func(err error) {
+		setCallsCount++
+		setErr = err
+	}
+	restoreFlagValues := replaceFlagValues(fls.FlagSet, func(value flag.Value) flag.Value {
+		return &setCallbackValue{Value: value, onSet: onSet}
+	})
+	err := fls.FlagSet.Parse(resolvedArgs)
+	restoreFlagValues()
+
+	if err == nil || setErr == nil || setCallsCount > len(flags) {
+		return err
+	}
+	f := flags[setCallsCount-1]
+	return fls.newArgsError(
+		&InvalidValueError{Flag: f.name, Value: f.value, Err: setErr, ArgIndex: fls.originalArgIndex(f.valueSpan.Index), IsBool: f.isBool},
+		f.valueSpan,
+	)
+}
+
+// setCallbackValue wraps a flag.Value calling `onSet` with the result of each Set() call
+type setCallbackValue struct {
+	flag.Value
+	onSet func(err error)
+}
+
+func (v *setCallbackValue) Set(value string) error {
+	err := v.Value.Set(value)
+	v.onSet(err)
+	return err
+}
+
+func (v *setCallbackValue) IsBoolFlag() bool {
+	return isBoolFlagValue(v.Value)
+}
+
+// replaceFlagValues temporarily replaces values of all flags with the values returned by `replace`.
+// Returns a function restoring the values
+func replaceFlagValues(flagSet *flag.FlagSet, replace func(value flag.Value) flag.Value) (restore func()) {
+	replaced := make(map[*flag.Flag]flag.Value)
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if newValue := replace(f.Value); newValue != f.Value {
+			replaced[f] = f.Value
+			f.Value = newValue
+		}
+	})
+	return func() {
+		for f, value := range replaced {
+			f.Value = value
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package flago
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownFlagError is returned by Parse() if a passed flag is not defined
+type UnknownFlagError struct {
+	Flag string
+	// ArgIndex is the index of the flag arg in the args passed to Parse()
+	// (the index of the `@path` arg if the flag is read from a response file)
+	ArgIndex int
+	// Suggestions are the closest registered flag names if SetUnknownFlagSuggestions(true) is set
+	Suggestions []string
+}
+
+func (e *UnknownFlagError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return "flag provided but not defined: -" + e.Flag
+	}
+	return fmt.Sprintf(
+		"flag provided but not defined: -%s (did you mean -%s?)",
+		e.Flag, strings.Join(e.Suggestions, " or -"),
+	)
+}
+
+// AmbiguousFlagError is returned by Parse() if a passed unknown flag name matches multiple registered
+// flag names (see SetPrefixMatching, SetNameNormalizer)
+type AmbiguousFlagError struct {
+	Flag string
+	// Candidates are the matching registered flag names (sorted)
+	Candidates []string
+	// ArgIndex is the index of the flag arg in the args passed to Parse()
+	// (the index of the `@path` arg if the flag is read from a response file)
+	ArgIndex int
+}
+
+func (e *AmbiguousFlagError) Error() string {
+	return fmt.Sprintf("ambiguous flag -%s: could be -%s", e.Flag, strings.Join(e.Candidates, " or -"))
+}
+
+// InvalidValueError is returned by Parse() if flag.Value.Set() of a passed flag (or a value provided by
+// a Source) returns an error
+type InvalidValueError struct {
+	Flag string
+	// Value is the passed value or cmdargs.RedactedValue if the flag is secret
+	Value string
+	// Err is the error returned by flag.Value.Set()
+	Err error
+	// ArgIndex is the index of the arg containing the value in the args passed to Parse()
+	// (the index of the `@path` arg if the value is read from a response file).
+	// -1 if the value is provided by Source
+	ArgIndex int
+	IsBool   bool
+	// Source is the source that provided the value. Nil if the value is passed in the args
+	Source Source
+}
+
+func (e *InvalidValueError) Error() string {
+	if e.Source != nil {
+		return fmt.Sprintf("invalid value %q for flag -%s provided by source: %v", e.Value, e.Flag, e.Err)
+	}
+	if e.IsBool {
+		return fmt.Sprintf("invalid boolean value %q for -%s: %v", e.Value, e.Flag, e.Err)
+	}
+	return fmt.Sprintf("invalid value %q for flag -%s: %v", e.Value, e.Flag, e.Err)
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// MissingValueError is returned by Parse() if a non-bool flag is the last arg and has no value
+type MissingValueError struct {
+	Flag string
+	// ArgIndex is the index of the flag arg in the args passed to Parse()
+	// (the index of the `@path` arg if the flag is read from a response file)
+	ArgIndex int
+}
+
+func (e *MissingValueError) Error() string {
+	return "flag needs an argument: -" + e.Flag
+}
+
+// RequiredFlagError is returned by Parse() if none of the names of a required flag is passed.
+// It wraps ErrIsRequired
+type RequiredFlagError struct {
+	// Names are all names (aliases) of the flag
+	Names []string
+}
+
+func (e *RequiredFlagError) Error() string {
+	return fmt.Sprintf(`%s: "%s"`, ErrIsRequired, strings.Join(e.Names, `"/"`))
+}
+
+func (e *RequiredFlagError) Unwrap() error {
+	return ErrIsRequired
+}
+
+// MultipleAliasesError is returned by Parse() if multiple aliases of the same flag are passed
+// (see SetAllowParsingMultipleAliases). It wraps ErrMultipleAliases
+type MultipleAliasesError struct {
+	First  string
+	Second string
+}
+
+func (e *MultipleAliasesError) Error() string {
+	return fmt.Sprintf(`%s: "%s" and "%s"`, ErrMultipleAliases, e.First, e.Second)
+}
+
+func (e *MultipleAliasesError) Unwrap() error {
+	return ErrMultipleAliases
+}
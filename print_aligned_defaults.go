@@ -0,0 +1,225 @@
+package flago
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// UsageWidthAuto can be passed to FlagSet.SetUsageWidth to use the terminal width taken from
+// COLUMNS environment variable (or defaultUsageWidth if it's not set)
+const UsageWidthAuto = -1
+
+const (
+	defaultUsageWidth = 80
+	// minUsageTextWidth is the minimal width of wrapped usage text. If there is not enough space
+	// to the right of the names column, usage text is printed on the next line
+	minUsageTextWidth = 20
+	usageIndent       = "  "
+	usageColumnsGap   = "  "
+)
+
+// getTerminalWidth returns the terminal width specified in COLUMNS environment variable
+func getTerminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultUsageWidth
+}
+
+func resolveUsageWidth(width int) int {
+	if width > 0 {
+		return width
+	}
+	return getTerminalWidth()
+}
+
+type alignedUsageItem struct {
+	names string
+	usage string
+}
+
+// PrintFlagSetAlignedDefaults prints flag names and usage grouping alternative flag names.
+// Unlike PrintFlagSetDefaults, flag names are aligned in a column and usage messages are wrapped to
+// fit the given `width`. If `width` <= 0, terminal width from COLUMNS environment variable is used
+func PrintFlagSetAlignedDefaults(flagSet *FlagSet, width int) {
+	width = resolveUsageWidth(width)
+	items := getAlignedUsageItems(flagSet)
+
+	namesColumnWidth := 0
+	for _, item := range items {
+		if namesWidth := utf8.RuneCountInString(item.names); namesWidth > namesColumnWidth {
+			namesColumnWidth = namesWidth
+		}
+	}
+	// don't let long names occupy the space needed for usage text
+	maxNamesColumnWidth := width/2 - len(usageIndent) - len(usageColumnsGap)
+	if namesColumnWidth > maxNamesColumnWidth {
+		namesColumnWidth = maxNamesColumnWidth
+	}
+
+	layout := alignedUsageLayout{
+		namesColumnWidth: namesColumnWidth,
+		usageTextIndent:  len(usageIndent) + namesColumnWidth + len(usageColumnsGap),
+		isUsageInline:    true,
+	}
+	layout.usageTextWidth = width - layout.usageTextIndent
+	if layout.usageTextWidth < minUsageTextWidth {
+		// not enough space, print usage text on the next line
+		layout.usageTextIndent = len(usageIndent) * 3
+		layout.usageTextWidth = width - layout.usageTextIndent
+		layout.isUsageInline = false
+	}
+
+	output := flagSet.Output()
+	for _, item := range items {
+		writeAlignedUsageItem(output, item, layout)
+	}
+}
+
+type alignedUsageLayout struct {
+	namesColumnWidth int
+	usageTextIndent  int
+	usageTextWidth   int
+	// isUsageInline indicates that usage text starts on the same line with flag names
+	isUsageInline bool
+}
+
+func writeAlignedUsageItem(output io.Writer, item alignedUsageItem, layout alignedUsageLayout) {
+	sb := strings.Builder{}
+	sb.WriteString(usageIndent)
+	sb.WriteString(item.names)
+
+	lines := wrapText(item.usage, layout.usageTextWidth)
+	if len(lines) > 0 {
+		namesWidth := utf8.RuneCountInString(item.names)
+		if layout.isUsageInline && namesWidth <= layout.namesColumnWidth {
+			sb.WriteString(strings.Repeat(" ", layout.namesColumnWidth-namesWidth))
+			sb.WriteString(usageColumnsGap)
+		} else {
+			sb.WriteString("\n")
+			sb.WriteString(strings.Repeat(" ", layout.usageTextIndent))
+		}
+		for i, line := range lines {
+			if i > 0 {
+				sb.WriteString("\n")
+				if line != "" {
+					sb.WriteString(strings.Repeat(" ", layout.usageTextIndent))
+				}
+			}
+			sb.WriteString(line)
+		}
+	}
+	sb.WriteString("\n")
+	_, _ = io.WriteString(output, sb.String())
+}
+
+func getAlignedUsageItems(flagSet *FlagSet) (res []alignedUsageItem) {
+	indexedFlagNames := indexFormalFlagNames(flagSet)
+	seenFlags := make(map[*flagNames]struct{})
+
+	flagSet.VisitAll(func(f *flag.Flag) {
+		fNames, ok := indexedFlagNames[f.Name]
+		if !ok {
+			return
+		}
+		if _, seen := seenFlags[fNames]; seen || fNames.isHidden {
+			return
+		}
+		seenFlags[fNames] = struct{}{}
+
+		typeName, usage := flag.UnquoteUsage(&flag.Flag{Name: f.Name, Usage: f.Usage, Value: unwrapFlagValue(f.Value)})
+		names := strings.Builder{}
+		names.WriteString(fNames.getUsageNames())
+		if typeName != "" {
+			names.WriteString(" ")
+			names.WriteString(typeName)
+		}
+		if fNames.isRequired {
+			usage = strings.TrimSuffix("* "+usage, " ")
+		}
+		if !fNames.isSecret && !isZeroDefaultValue(f) {
+			usage += getDefaultValueUsageSuffix(f)
+		}
+		usage += fNames.getAllowedValuesMark()
+		usage += fNames.getDeprecatedMark()
+		res = append(res, alignedUsageItem{
+			names: names.String(),
+			usage: usage,
+		})
+	})
+	return res
+}
+
+// getDefaultValueUsageSuffix returns the default value message in the same format as std flag package uses
+func getDefaultValueUsageSuffix(f *flag.Flag) string {
+	if isStringFlagValue(f.Value) {
+		return fmt.Sprintf(" (default %q)", f.DefValue)
+	}
+	return fmt.Sprintf(" (default %v)", f.DefValue)
+}
+
+// isZeroDefaultValue determines whether the string represents the zero value for a flag.
+// It follows the logic of the std flag package
+func isZeroDefaultValue(f *flag.Flag) bool {
+	valueType := reflect.TypeOf(unwrapFlagValue(f.Value))
+	var zeroValue reflect.Value
+	if valueType.Kind() == reflect.Pointer {
+		zeroValue = reflect.New(valueType.Elem())
+	} else {
+		zeroValue = reflect.Zero(valueType)
+	}
+	zeroFlagValue, ok := zeroValue.Interface().(flag.Value)
+	if !ok {
+		return f.DefValue == ""
+	}
+	return func() (isZero bool) {
+		defer func() {
+			// String() of a zero value of some user types can panic
+			if recover() != nil {
+				isZero = f.DefValue == ""
+			}
+		}()
+		return f.DefValue == zeroFlagValue.String()
+	}()
+}
+
+// isStringFlagValue checks if the value is created by flag.StringVar(). The std flag package
+// quotes default values of such flags in usage
+func isStringFlagValue(value flag.Value) bool {
+	return reflect.TypeOf(unwrapFlagValue(value)).String() == "*flag.stringValue"
+}
+
+// wrapText splits the text into lines not longer than `width` runes (if possible) breaking it
+// at whitespaces. Line breaks in the text are preserved
+func wrapText(text string, width int) (lines []string) {
+	if text == "" {
+		return nil
+	}
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		lineWidth := utf8.RuneCountInString(line)
+		for _, word := range words[1:] {
+			wordWidth := utf8.RuneCountInString(word)
+			if lineWidth+1+wordWidth > width {
+				lines = append(lines, line)
+				line, lineWidth = word, wordWidth
+			} else {
+				line += " " + word
+				lineWidth += 1 + wordWidth
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,103 @@
+package flago
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignedUsage(t *testing.T) {
+	type nestedStruct struct {
+		X string `flag:"x" flagUsage:"usage_x"`
+		Y string `flags:"y,yy" flagUsage:"usage_y is long enough to be wrapped to the next line" flagRequired:"true"`
+	}
+	type simpleStruct struct {
+		S  string       `flag:"s" flagUsage:"usage_s" flagRequired:"true"`
+		B  bool         `flags:"b1,b2" flagUsage:"usage_b12"`
+		Z  bool         `flag:"z" flagUsage:"line1\nline2"`
+		ZZ bool         `flag:"zz"`
+		I  int          `flag:"i" flagUsage:"usage_i"`
+		Sp *string      `flag:"sp" flagUsage:"usage_sp"`
+		N  nestedStruct `flagPrefix:"n-"`
+	}
+	structVal := simpleStruct{
+		S: "s_default",
+		I: 3,
+	}
+	fls := NewFlagSet("fls_name", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&structVal))
+
+	t.Run("wide", func(t *testing.T) {
+		fls.SetUsageWidth(60)
+		expectedUsage := `Usage of fls_name:
+  -b1 -b2            usage_b12
+  -i int             usage_i (default 3)
+  -n-x string        usage_x
+  -n-y -n-yy string  * usage_y is long enough to be wrapped
+                     to the next line
+  -s string          usage_s (default "s_default")
+  -sp string         usage_sp
+  -z                 line1
+                     line2
+  -zz
+`
+		require.Equal(t, expectedUsage, captureOutput(fls, fls.Usage))
+	})
+
+	t.Run("narrow", func(t *testing.T) {
+		fls.SetUsageWidth(36)
+		expectedUsage := `Usage of fls_name:
+  -b1 -b2
+      usage_b12
+  -i int
+      usage_i (default 3)
+  -n-x string
+      usage_x
+  -n-y -n-yy string
+      * usage_y is long enough to be
+      wrapped to the next line
+  -s string
+      usage_s (default "s_default")
+  -sp string
+      usage_sp
+  -z
+      line1
+      line2
+  -zz
+`
+		require.Equal(t, expectedUsage, captureOutput(fls, fls.Usage))
+	})
+
+	t.Run("columns_env", func(t *testing.T) {
+		t.Setenv("COLUMNS", "36")
+		fls.SetUsageWidth(UsageWidthAuto)
+		narrowUsage := captureOutput(fls, fls.Usage)
+		fls.SetUsageWidth(36)
+		require.Equal(t, captureOutput(fls, fls.Usage), narrowUsage)
+	})
+}
+
+func TestWrapText(t *testing.T) {
+	require.Nil(t, wrapText("", 10))
+	require.Equal(t, []string{"abc def", "ghi"}, wrapText("abc def ghi", 7))
+	require.Equal(t, []string{"abcdefghijk", "l"}, wrapText("abcdefghijk l", 7))
+	require.Equal(t, []string{"abc", "", "def"}, wrapText("abc\n\ndef", 7))
+	// width is measured in runes
+	require.Equal(t, []string{"привет мир", "ёж"}, wrapText("привет мир ёж", 10))
+}
+
+func TestAlignedUsageNonASCII(t *testing.T) {
+	type testStruct struct {
+		Name string `flag:"имя" flagUsage:"имя пользователя"`
+		Age  int    `flag:"age" flagUsage:"возраст"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	fls.SetUsageWidth(40)
+	expectedUsage := `Usage:
+  -age int     возраст
+  -имя string  имя пользователя
+`
+	require.Equal(t, expectedUsage, captureOutput(fls, fls.Usage))
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"reflect"
 	"regexp"
 	"strings"
 )
@@ -19,7 +20,16 @@ func printUsageTitle(flagSet *flag.FlagSet, name string) {
 // DefaultUsage prints the default FlagSet usage to flagSet.Output grouping alternative flag names
 func DefaultUsage(flagSet *FlagSet) {
 	printUsageTitle(flagSet.FlagSet, flagSet.Name())
-	PrintFlagSetDefaults(flagSet)
+	flagSet.PrintDefaults()
+}
+
+// stdDefaultUsagePointer is the code pointer of the Usage that flag.NewFlagSet() assigns. All FlagSets share it
+var stdDefaultUsagePointer = reflect.ValueOf(flag.NewFlagSet("", flag.ContinueOnError).Usage).Pointer()
+
+// isStdDefaultUsage returns true if `usage` is the default Usage of flag.FlagSet that prints all flags
+// and their defaults as is
+func isStdDefaultUsage(usage func()) bool {
+	return reflect.ValueOf(usage).Pointer() == stdDefaultUsagePointer
 }
 
 type mutatorWriter struct {
@@ -45,12 +55,22 @@ func (w *mutatorWriter) Write(p []byte) (n int, err error) {
 type flagNames struct {
 	f          *flag.Flag
 	isRequired bool
-	names      []string
+	isHidden   bool
+	isSecret   bool
+	// deprecation is not empty if all names are deprecated
+	deprecation string
+	// names contains not deprecated names if there are any
+	names []string
+	// deprecatedAliases contains deprecated names if there are not deprecated names
+	deprecatedAliases []string
+	// enum contains allowed values of the field with `flagEnum` tag
+	enum []string
 }
 
 // indexFormalFlagNames returns a map of flag names to flag names grouped by flag value
 func indexFormalFlagNames(flagSet *FlagSet) map[string]*flagNames {
 	namesByValue := make(map[flag.Value]*flagNames)
+	allNamesByValue := make(map[flag.Value][]string)
 	flagSet.VisitAll(func(f *flag.Flag) {
 		fNames, ok := namesByValue[f.Value]
 		if !ok {
@@ -60,19 +80,69 @@ func indexFormalFlagNames(flagSet *FlagSet) map[string]*flagNames {
 			if _, isRequired := flagSet.requiredFlagNames[f.Name]; isRequired {
 				fNames.isRequired = true
 			}
+			if _, isHidden := flagSet.hiddenFlagNames[f.Name]; isHidden {
+				fNames.isHidden = true
+			}
+			fNames.isSecret = flagSet.isSecretFlag(f.Name)
+			if ev, isEnum := f.Value.(*enumValue); isEnum {
+				fNames.enum = ev.enum
+			}
 			namesByValue[f.Value] = fNames
 		}
-		fNames.names = append(fNames.names, f.Name)
+		allNamesByValue[f.Value] = append(allNamesByValue[f.Value], f.Name)
+		if _, isDeprecated := flagSet.deprecatedFlagNames[f.Name]; !isDeprecated {
+			fNames.names = append(fNames.names, f.Name)
+		} else {
+			fNames.deprecatedAliases = append(fNames.deprecatedAliases, f.Name)
+		}
 	})
 	res := make(map[string]*flagNames)
-	for _, fNames := range namesByValue {
-		for _, name := range fNames.names {
+	for value, fNames := range namesByValue {
+		allNames := allNamesByValue[value]
+		if len(fNames.names) == 0 {
+			fNames.names = allNames
+			fNames.deprecatedAliases = nil
+			fNames.deprecation = flagSet.deprecatedFlagNames[allNames[0]]
+		}
+		for _, name := range allNames {
 			res[name] = fNames
 		}
 	}
 	return res
 }
 
+// getUsageNames returns a string with flag names separated by spaces
+func (fNames *flagNames) getUsageNames() string {
+	names := strings.Builder{}
+	for i, name := range fNames.names {
+		if i > 0 {
+			names.WriteString(" ")
+		}
+		names.WriteString("-")
+		names.WriteString(name)
+	}
+	return names.String()
+}
+
+// getDeprecatedMark returns the deprecation message of the flag or lists its deprecated aliases
+func (fNames *flagNames) getDeprecatedMark() string {
+	if fNames.deprecation != "" {
+		return fmt.Sprintf(" (deprecated: %s)", fNames.deprecation)
+	}
+	if len(fNames.deprecatedAliases) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (deprecated aliases: -%s)", strings.Join(fNames.deprecatedAliases, ", -"))
+}
+
+// getAllowedValuesMark returns the allowed values of the flag with `flagEnum` tag
+func (fNames *flagNames) getAllowedValuesMark() string {
+	if len(fNames.enum) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` (allowed: "%s")`, strings.Join(fNames.enum, `", "`))
+}
+
 var defaultsOutputItemNameSearchRegexp = regexp.MustCompile(`(?m)^\s*?-(.*?)\s`)
 var defaultsOutputItemNameReplaceRegexp = regexp.MustCompile(`(?m)^(\s*?)(-.*?)(\s.*?)$`)
 
@@ -95,6 +165,24 @@ func addDefaultsRequiredMark(outputItem string) string {
 	return strings.Replace(outputItem, "\t", "\t* ", 1)
 }
 
+func removeDefaultsDefaultValue(outputItem string, f *flag.Flag) string {
+	if isZeroDefaultValue(f) {
+		return outputItem
+	}
+	suffix := getDefaultValueUsageSuffix(f)
+	if strings.HasSuffix(outputItem, suffix+"\n") {
+		return strings.TrimSuffix(outputItem, suffix+"\n") + "\n"
+	}
+	return outputItem
+}
+
+func addDefaultsMark(outputItem, mark string) string {
+	if strings.HasSuffix(outputItem, "\n") {
+		return outputItem[:len(outputItem)-1] + mark + "\n"
+	}
+	return outputItem + mark
+}
+
 // PrintFlagSetDefaults prints flag names and usage grouping alternative flag names
 func PrintFlagSetDefaults(flagSet *FlagSet) {
 	// The implementation of this method is dirty and relies on the internal implementation details
@@ -114,22 +202,23 @@ func PrintFlagSetDefaults(flagSet *FlagSet) {
 	flagSet.SetOutput(newFilteringWriter(originalOutput, func(s string) string {
 		if name := getDefaultsOutputItemName(s); name != "" {
 			if fNames, ok := indexedFlagNames[name]; ok {
-				if _, seen := seenFlags[fNames]; !seen {
+				if _, seen := seenFlags[fNames]; !seen && !fNames.isHidden {
 					seenFlags[fNames] = struct{}{}
-					if len(fNames.names) > 1 {
-						names := strings.Builder{}
-						for i, name := range fNames.names {
-							if i > 0 {
-								names.WriteString(" ")
-							}
-							names.WriteString("-")
-							names.WriteString(name)
-						}
-						s = replaceDefaultsOutputItemName(s, names.String())
+					if len(fNames.names) > 1 || fNames.names[0] != name {
+						s = replaceDefaultsOutputItemName(s, fNames.getUsageNames())
 					}
 					if fNames.isRequired {
 						s = addDefaultsRequiredMark(s)
 					}
+					if fNames.isSecret {
+						s = removeDefaultsDefaultValue(s, fNames.f)
+					}
+					if mark := fNames.getAllowedValuesMark(); mark != "" {
+						s = addDefaultsMark(s, mark)
+					}
+					if mark := fNames.getDeprecatedMark(); mark != "" {
+						s = addDefaultsMark(s, mark)
+					}
 					return s
 				}
 				return "" // skip
@@ -138,5 +227,8 @@ func PrintFlagSetDefaults(flagSet *FlagSet) {
 		return s
 	}))
 
+	restoreFlagValues := unwrapFlagValues(flagSet.FlagSet)
+	defer restoreFlagValues()
+
 	flagSet.FlagSet.PrintDefaults()
 }
@@ -0,0 +1,17 @@
+package flago
+
+import "github.com/cardinalby/go-struct-flags/cmdargs"
+
+// isSecretFlag returns true if the flag is registered for a field tagged with `flagSecret`
+func (fls *FlagSet) isSecretFlag(flagName string) bool {
+	_, isSecret := fls.secretFlagNames[flagName]
+	return isSecret
+}
+
+// redactSecretValue returns cmdargs.RedactedValue if the flag is secret or `value` otherwise
+func (fls *FlagSet) redactSecretValue(flagName string, value string) string {
+	if fls.isSecretFlag(flagName) {
+		return cmdargs.RedactedValue
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package flago
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+)
+
+const sliceValueElemFlagName = "elem"
+
+// sliceValue is a flag.Value for slice fields with elements of types supported by `flag` package.
+// Each Set() call parses the element and appends it to the slice. The first call replaces the default
+// value of the field, so the field contains only passed values if the flag is passed.
+// The values are reset at the start of each Parse() call (see resetSliceValues), so the first Set() call
+// in the next Parse() replaces the values set by the previous one
+type sliceValue struct {
+	slice reflect.Value
+	// elem is a value the elements are parsed to
+	elem reflect.Value
+	// elemFlag is registered in a private FlagSet to parse elem using `flag` package
+	elemFlag *flag.Flag
+	isSet    bool
+}
+
+// newSliceValue returns nil if elements of the slice are not supported
+func newSliceValue(fieldValue reflect.Value) *sliceValue {
+	elem := reflect.New(fieldValue.Type().Elem()).Elem()
+	elemVarRegister := getPrimitiveVarRegister(elem, reflect.Zero(elem.Type()))
+	if elemVarRegister == nil {
+		return nil
+	}
+	elemFlagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	_ = elemVarRegister(elemFlagSet, sliceValueElemFlagName, "")
+	return &sliceValue{
+		slice:    getAccessibleValue(fieldValue),
+		elem:     elem,
+		elemFlag: elemFlagSet.Lookup(sliceValueElemFlagName),
+	}
+}
+
+func (v *sliceValue) Set(value string) error {
+	if err := v.elemFlag.Value.Set(value); err != nil {
+		return err
+	}
+	if !v.isSet {
+		v.slice.Set(reflect.MakeSlice(v.slice.Type(), 0, 1))
+		v.isSet = true
+	}
+	v.slice.Set(reflect.Append(v.slice, v.elem))
+	return nil
+}
+
+// resetSliceValues makes the next Set() call of sliceValue flags replace the current values
+func resetSliceValues(flagSet *flag.FlagSet) {
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if sv, ok := unwrapFlagValue(f.Value).(*sliceValue); ok {
+			sv.isSet = false
+		}
+	})
+}
+
+// String returns comma-separated elements
+func (v *sliceValue) String() string {
+	return strings.Join(v.getStrings(), ",")
+}
+
+// getStrings returns string representations of the elements
+func (v *sliceValue) getStrings() []string {
+	if v == nil || !v.slice.IsValid() {
+		return nil
+	}
+	res := make([]string, v.slice.Len())
+	for i := range res {
+		v.elem.Set(v.slice.Index(i))
+		res[i] = v.elemFlag.Value.String()
+	}
+	return res
+}
+
+// getFlagValueStrings returns the elements of the slice value or the single value of other flag values
+func getFlagValueStrings(value flag.Value) []string {
+	if sv, ok := unwrapFlagValue(value).(*sliceValue); ok {
+		return sv.getStrings()
+	}
+	return []string{value.String()}
+}
@@ -0,0 +1,62 @@
+package flago
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceFields(t *testing.T) {
+	type testStruct struct {
+		Strings   []string        `flags:"s,str"`
+		Ints      []int           `flag:"i"`
+		Durations []time.Duration `flag:"d"`
+		Formats   []string        `flag:"f" flagEnum:"json,text"`
+	}
+	newFlagSet := func() (*FlagSet, *testStruct) {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		fls.SetOutput(io.Discard)
+		structVal := &testStruct{
+			Strings: []string{"default"},
+			Ints:    []int{1},
+		}
+		require.NoError(t, fls.StructVar(structVal))
+		return fls, structVal
+	}
+
+	fls, structVal := newFlagSet()
+	require.NoError(t, fls.Parse([]string{"-s", "a", "-i", "2", "-s", "b", "-d", "1m", "-i", "3", "-f", "json"}))
+	require.Equal(t, &testStruct{
+		Strings:   []string{"a", "b"},
+		Ints:      []int{2, 3},
+		Durations: []time.Duration{time.Minute},
+		Formats:   []string{"json"},
+	}, structVal)
+	require.Equal(t, []string{
+		"-d", "1m0s", "-f", "json", "-i", "2", "-i", "3", "-s", "a", "-s", "b",
+	}, fls.ToArgs())
+	require.Equal(t, "a,b", fls.Lookup("s").Value.String())
+	require.Equal(t, "default", fls.Lookup("s").DefValue)
+
+	fls, structVal = newFlagSet()
+	require.NoError(t, fls.Parse(nil))
+	require.Equal(t, []string{"default"}, structVal.Strings)
+	require.Equal(t, []int{1}, structVal.Ints)
+	require.Nil(t, structVal.Durations)
+
+	// the next Parse() replaces the values instead of appending to them
+	fls, structVal = newFlagSet()
+	require.NoError(t, fls.Parse([]string{"-s", "a", "-s", "b"}))
+	require.NoError(t, fls.Parse([]string{"-s", "c", "-i", "4"}))
+	require.Equal(t, []string{"c"}, structVal.Strings)
+	require.Equal(t, []int{4}, structVal.Ints)
+
+	fls, _ = newFlagSet()
+	require.ErrorContains(t, fls.Parse([]string{"-i", "x"}), `invalid value "x" for flag -i`)
+
+	fls, _ = newFlagSet()
+	require.ErrorIs(t, fls.Parse([]string{"-f", "json", "-f", "xml"}), ErrNotAllowedValue)
+}
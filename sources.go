@@ -0,0 +1,133 @@
+package flago
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+)
+
+// Source provides values for the flags that were not passed in the command line
+type Source interface {
+	// Lookup returns the value for the flag with the given primary name.
+	// `fieldPath` is a dot-separated path of the struct field the flag is registered for (e.g. "Sender.Name")
+	// or empty string if the flag is registered in the wrapped FlagSet directly.
+	// `ok` should be false if the source doesn't have a value for the flag
+	Lookup(flagName string, fieldPath string) (value string, ok bool, err error)
+}
+
+// MultiValueSource is a Source that can provide multiple values for a flag (e.g. repeated keys in
+// a config file). If a source implements it, LookupAll is used instead of Lookup and each value is set
+// in the same way as if the flag was passed multiple times: slice fields get all the values,
+// other fields get the last one
+type MultiValueSource interface {
+	Source
+	LookupAll(flagName string, fieldPath string) (values []string, ok bool, err error)
+}
+
+// SourceFunc is an adapter to allow the use of ordinary functions as Source
+type SourceFunc func(flagName string, fieldPath string) (value string, ok bool, err error)
+
+// Lookup calls f(flagName, fieldPath)
+func (f SourceFunc) Lookup(flagName string, fieldPath string) (value string, ok bool, err error) {
+	return f(flagName, fieldPath)
+}
+
+// valueErrorWrapper can be implemented by a Source to add the location of an invalid value to the error
+type valueErrorWrapper interface {
+	wrapValueError(flagName string, err error) error
+}
+
+type prioritizedSource struct {
+	source   Source
+	priority int
+}
+
+// AddSource adds a source of values for the flags that were not passed in the command line.
+// Sources are consulted in Parse() in order of descending `priority` (sources with equal priority are
+// consulted in order of adding), the first found value is used.
+// The values are set using flag.Value.Set() in the same way as the values passed in the command line,
+// so fields (including pointer fields) are treated as if the flags were passed
+func (fls *FlagSet) AddSource(src Source, priority int) {
+	fls.sources = append(fls.sources, prioritizedSource{
+		source:   src,
+		priority: priority,
+	})
+	sort.SliceStable(fls.sources, func(i, j int) bool {
+		return fls.sources[i].priority > fls.sources[j].priority
+	})
+}
+
+// applySources sets values from the sources for the flags that were not parsed
+func (fls *FlagSet) applySources() error {
+	if len(fls.sources) == 0 {
+		return nil
+	}
+	parsedFlagNames := stdutil.GetExistingFlagNames(fls.FlagSet)
+	var errs []error
+	for _, field := range fls.getFlagFields() {
+		if isAnyFlagNameParsed(parsedFlagNames, field) {
+			continue
+		}
+		if err := fls.applySourcesToFlagField(field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return joinErr(errs...)
+}
+
+func (fls *FlagSet) applySourcesToFlagField(field flagField) error {
+	flagName := field.flagNames[0]
+	for _, src := range fls.sources {
+		values, ok, err := lookupSourceValues(src.source, flagName, field.getFieldPath())
+		if err != nil {
+			return fmt.Errorf("source lookup for flag -%s: %w", flagName, err)
+		}
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			if err := fls.Set(flagName, value); err != nil {
+				var valueErr error = &InvalidValueError{
+					Flag:     flagName,
+					Value:    fls.redactSecretValue(flagName, value),
+					Err:      err,
+					ArgIndex: -1,
+					IsBool:   isBoolFlagValue(field.flag.Value),
+					Source:   src.source,
+				}
+				if wrapper, ok := src.source.(valueErrorWrapper); ok {
+					valueErr = wrapper.wrapValueError(flagName, valueErr)
+				}
+				return valueErr
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+func lookupSourceValues(src Source, flagName string, fieldPath string) (values []string, ok bool, err error) {
+	if multiValueSource, isMultiValue := src.(MultiValueSource); isMultiValue {
+		return multiValueSource.LookupAll(flagName, fieldPath)
+	}
+	value, ok, err := src.Lookup(flagName, fieldPath)
+	return []string{value}, ok, err
+}
+
+func isAnyFlagNameParsed(parsedFlagNames map[string]struct{}, field flagField) bool {
+	if field.namedFlagsField == nil {
+		for _, flagName := range field.flagNames {
+			if _, isParsed := parsedFlagNames[flagName]; isParsed {
+				return true
+			}
+		}
+		return false
+	}
+	for _, namedFlagField := range field.namedFlagsField.fields {
+		if _, isParsed := parsedFlagNames[namedFlagField.flagName]; isParsed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package flago
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapSource map[string]string
+
+func (s mapSource) Lookup(flagName string, _ string) (string, bool, error) {
+	value, ok := s[flagName]
+	return value, ok, nil
+}
+
+func TestSources(t *testing.T) {
+	type nested struct {
+		N *int `flag:"n"`
+	}
+	type testStruct struct {
+		Str       string  `flags:"s,str"`
+		Int       int     `flag:"i" flagRequired:"true"`
+		StrP      *string `flag:"sp"`
+		BoolP     *bool   `flag:"bp"`
+		Nested    nested  `flagPrefix:"nested-"`
+		Untouched string  `flag:"u"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	std := fls.String("std", "", "")
+	structVal := testStruct{
+		Untouched: "default",
+	}
+	require.NoError(t, fls.StructVar(&structVal))
+
+	var lookedUp []string
+	fls.AddSource(SourceFunc(func(flagName string, fieldPath string) (string, bool, error) {
+		lookedUp = append(lookedUp, flagName+":"+fieldPath)
+		return "", false, nil
+	}), 0)
+	fls.AddSource(mapSource{"s": "low", "i": "1", "sp": "low"}, 1)
+	fls.AddSource(mapSource{"s": "high", "bp": "true", "nested-n": "2", "std": "std_val"}, 2)
+
+	require.NoError(t, fls.Parse([]string{"-sp", "cmd"}))
+	require.Equal(t, "high", structVal.Str)
+	require.Equal(t, 1, structVal.Int)
+	require.Equal(t, "cmd", *structVal.StrP)
+	require.Equal(t, true, *structVal.BoolP)
+	require.Equal(t, 2, *structVal.Nested.N)
+	require.Equal(t, "default", structVal.Untouched)
+	require.Equal(t, "std_val", *std)
+	require.Equal(t, []string{"u:Untouched"}, lookedUp)
+}
+
+func TestSourceAliasPassed(t *testing.T) {
+	type testStruct struct {
+		Str string `flags:"s,str"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	structVal := testStruct{}
+	require.NoError(t, fls.StructVar(&structVal))
+	fls.AddSource(mapSource{"s": "source"}, 0)
+
+	require.NoError(t, fls.Parse([]string{"-str", "cmd"}))
+	require.Equal(t, "cmd", structVal.Str)
+}
+
+func TestSourceErrors(t *testing.T) {
+	type testStruct struct {
+		Int    int `flag:"i"`
+		Secret int `flag:"secret" flagSecret:"true"`
+	}
+	newFlagSet := func(src Source) *FlagSet {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		fls.Usage = func() {}
+		require.NoError(t, fls.StructVar(&testStruct{}))
+		fls.AddSource(src, 0)
+		return fls
+	}
+
+	fls := newFlagSet(mapSource{"i": "abc", "secret": "qwerty"})
+	var err error
+	output := captureOutput(fls, func() {
+		err = fls.Parse(nil)
+	})
+	expectedErrorMsg := "invalid value \"abc\" for flag -i provided by source: parse error\n" +
+		"invalid value \"******\" for flag -secret provided by source: parse error"
+	require.EqualError(t, err, expectedErrorMsg)
+	require.Equal(t, expectedErrorMsg+"\n", output)
+	var invalidValueErr *InvalidValueError
+	require.ErrorAs(t, err, &invalidValueErr)
+	require.Equal(t, "i", invalidValueErr.Flag)
+	require.Equal(t, "abc", invalidValueErr.Value)
+	require.Equal(t, -1, invalidValueErr.ArgIndex)
+	require.Equal(t, mapSource{"i": "abc", "secret": "qwerty"}, invalidValueErr.Source)
+
+	lookupErr := errors.New("lookup error")
+	fls = newFlagSet(SourceFunc(func(string, string) (string, bool, error) {
+		return "", false, lookupErr
+	}))
+	_ = captureOutput(fls, func() {
+		err = fls.Parse(nil)
+	})
+	require.ErrorIs(t, err, lookupErr)
+}
@@ -0,0 +1,21 @@
+package stdutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadFormalTagNames reads FormalTagNames from JSON file containing an object where keys are flag names
+// and values indicate bool flags, e.g. {"verbose": true, "output": false}
+func LoadFormalTagNames(path string) (FormalTagNames, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res FormalTagNames
+	if err := json.Unmarshal(content, &res); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return res, nil
+}
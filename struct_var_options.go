@@ -0,0 +1,108 @@
+package flago
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Option customizes registration of a struct by StructVarOpts()
+type Option func(opts *structVarOptions)
+
+type structVarOptions struct {
+	flagsPrefix       string
+	usagePrefix       string
+	ignoredFields     []any
+	onlyFields        []any
+	requiredByDefault bool
+	namingStrategy    NamingStrategy
+}
+
+// IgnoreFields option specifies pointers to fields that should be ignored and not registered as flags.
+// Flags of the ignored fields are stripped with their known types if SetIgnoreUnknown(true) is set
+func IgnoreFields(fields ...any) Option {
+	return func(opts *structVarOptions) {
+		opts.ignoredFields = append(opts.ignoredFields, fields...)
+	}
+}
+
+// OnlyFields option specifies pointers to fields that should be registered, other fields are skipped.
+// A pointer to a nested struct field selects all its fields.
+// StructVarOpts returns an error if a pointer doesn't point to a field of the struct defining a flag or
+// a nested struct (e.g. to an untagged or `flag:"-"` field)
+func OnlyFields(fields ...any) Option {
+	return func(opts *structVarOptions) {
+		opts.onlyFields = append(opts.onlyFields, fields...)
+	}
+}
+
+// WithPrefix option specifies a prefix for all flag names of the struct
+func WithPrefix(flagsPrefix string) Option {
+	return func(opts *structVarOptions) {
+		opts.flagsPrefix = flagsPrefix
+	}
+}
+
+// WithUsagePrefix option specifies a prefix for all usage messages of the struct flags
+func WithUsagePrefix(usagePrefix string) Option {
+	return func(opts *structVarOptions) {
+		opts.usagePrefix = usagePrefix
+	}
+}
+
+// WithRequiredByDefault option makes the fields without `flagRequired` tag required if `required` is true
+func WithRequiredByDefault(required bool) Option {
+	return func(opts *structVarOptions) {
+		opts.requiredByDefault = required
+	}
+}
+
+// WithNamingStrategy option overrides the naming strategy set by FlagSet.SetNamingStrategy for the struct
+func WithNamingStrategy(strategy NamingStrategy) Option {
+	return func(opts *structVarOptions) {
+		opts.namingStrategy = strategy
+	}
+}
+
+// fieldPointer identifies a field. Type is needed to distinguish a nested struct from its first field
+// having the same address
+type fieldPointer struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+}
+
+func newFieldPointer(fieldValue reflect.Value) fieldPointer {
+	return fieldPointer{ptr: fieldValue.Addr().UnsafePointer(), typ: fieldValue.Type()}
+}
+
+// newFieldPointersSet returns nil if `fields` is empty. Values of the map indicate that the pointer matches
+// a field of the struct, they are set by parentStructInfo.isFieldSelected
+func newFieldPointersSet(fields []any) (map[fieldPointer]bool, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	res := make(map[fieldPointer]bool, len(fields))
+	for i, field := range fields {
+		val := reflect.ValueOf(field)
+		if val.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf(`element %d: pointer expected, got %s`, i, val.Type().Name())
+		}
+		res[newFieldPointer(val.Elem())] = false
+	}
+	return res, nil
+}
+
+// checkFieldPointersMatched returns an error for the first of `fields` that doesn't match any field
+// of the struct defining a flag or a nested struct
+func checkFieldPointersMatched(fields []any, pointersSet map[fieldPointer]bool) error {
+	for i, field := range fields {
+		val := reflect.ValueOf(field).Elem()
+		if !pointersSet[newFieldPointer(val)] {
+			return fmt.Errorf(
+				`element %d: %s pointer doesn't point to a flag or nested struct field of the struct`,
+				i, val.Type().String(),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package flago
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructVarOpts(t *testing.T) {
+	type nested struct {
+		Host string `flag:"host"`
+		Port int    `flag:"port"`
+	}
+	type testStruct struct {
+		Name     string   `flag:"name" flagUsage:"name usage"`
+		Count    int      `flag:"count" flagRequired:"false"`
+		Ignored  string   `flag:"ignored"`
+		Nested   nested   `flagPrefix:"n-"`
+		Other    nested   `flagPrefix:"o-"`
+		Args     []string `flagArgs:"true"`
+		AutoName string
+	}
+
+	t.Run("prefixes", func(t *testing.T) {
+		structVal := testStruct{}
+		fls := NewFlagSet("", flag.ContinueOnError)
+		require.NoError(t, fls.StructVarOpts(&structVal,
+			WithPrefix("p."),
+			WithUsagePrefix("[p] "),
+			IgnoreFields(&structVal.Ignored),
+		))
+		require.Equal(t, "[p] name usage", fls.Lookup("p.name").Usage)
+		require.NotNil(t, fls.Lookup("p.n-host"))
+		require.Nil(t, fls.Lookup("p.ignored"))
+		require.Nil(t, fls.Lookup("AutoName"))
+	})
+
+	t.Run("only fields", func(t *testing.T) {
+		structVal := testStruct{}
+		fls := NewFlagSet("", flag.ContinueOnError)
+		require.NoError(t, fls.StructVarOpts(&structVal,
+			OnlyFields(&structVal.Name, &structVal.Nested, &structVal.Other.Port),
+		))
+		var names []string
+		fls.VisitAll(func(f *flag.Flag) {
+			names = append(names, f.Name)
+		})
+		require.Equal(t, []string{"n-host", "n-port", "name", "o-port"}, names)
+		require.NoError(t, fls.Parse([]string{"-name", "x", "rest"}))
+		require.Nil(t, structVal.Args)
+
+		// the first field of a nested struct has the same address as the struct
+		fls = NewFlagSet("", flag.ContinueOnError)
+		require.NoError(t, fls.StructVarOpts(&structVal, OnlyFields(&structVal.Other.Host)))
+		require.NotNil(t, fls.Lookup("o-host"))
+		require.Nil(t, fls.Lookup("o-port"))
+
+		require.ErrorContains(t,
+			NewFlagSet("", flag.ContinueOnError).StructVarOpts(&structVal, OnlyFields(structVal.Name)),
+			"invalid onlyFields: element 0: pointer expected",
+		)
+
+		otherVal := testStruct{}
+		require.EqualError(t,
+			NewFlagSet("", flag.ContinueOnError).StructVarOpts(&structVal,
+				OnlyFields(&structVal.Name, &otherVal.Other.Port),
+			),
+			"invalid onlyFields: element 1: int pointer doesn't point to a flag or nested struct field of the struct",
+		)
+
+		// untagged fields don't define flags
+		require.EqualError(t,
+			NewFlagSet("", flag.ContinueOnError).StructVarOpts(&structVal, OnlyFields(&structVal.AutoName)),
+			"invalid onlyFields: element 0: string pointer doesn't point to a flag or nested struct field of the struct",
+		)
+	})
+
+	t.Run("required by default", func(t *testing.T) {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		require.NoError(t, fls.StructVarOpts(&testStruct{}, WithRequiredByDefault(true)))
+		var err error
+		captureOutput(fls, func() {
+			err = fls.Parse([]string{"-name", "x", "-ignored", "", "-n-host", "h", "-n-port", "1", "-o-host", "h"})
+		})
+		require.EqualError(t, err, `flag is required: "o-port"`)
+	})
+
+	t.Run("naming strategy", func(t *testing.T) {
+		fls := NewFlagSet("", flag.ContinueOnError)
+		fls.SetNamingStrategy(KebabCase)
+		require.NoError(t, fls.StructVarOpts(&testStruct{}, WithNamingStrategy(SnakeCase)))
+		require.NotNil(t, fls.Lookup("auto_name"))
+
+		fls = NewFlagSet("", flag.ContinueOnError)
+		fls.SetNamingStrategy(KebabCase)
+		require.NoError(t, fls.StructVarOpts(&testStruct{}, WithNamingStrategy(nil)))
+		require.Nil(t, fls.Lookup("auto-name"))
+	})
+}
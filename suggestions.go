@@ -0,0 +1,87 @@
+package flago
+
+import (
+	"sort"
+
+	"github.com/cardinalby/go-struct-flags/stdutil"
+)
+
+const (
+	maxSuggestionDistance = 2
+	maxSuggestionsCount   = 3
+)
+
+// getUnknownFlagSuggestions returns up to maxSuggestionsCount registered flag names that are the closest
+// to the unknown `flagName`, sorted by distance and name
+func (fls *FlagSet) getUnknownFlagSuggestions(flagName string) []string {
+	type suggestion struct {
+		flagName string
+		distance int
+	}
+	var suggestions []suggestion
+	flagNameRunes := []rune(flagName)
+	for name := range stdutil.GetFormalFlagNames(fls.FlagSet) {
+		if _, isHidden := fls.hiddenFlagNames[name]; isHidden {
+			continue
+		}
+		if _, isDeprecated := fls.deprecatedFlagNames[name]; isDeprecated {
+			continue
+		}
+		distance := getDamerauLevenshteinDistance(flagNameRunes, []rune(name))
+		// distance < len(flagName) prevents suggesting arbitrary short names
+		if distance <= maxSuggestionDistance && distance < len(flagNameRunes) {
+			suggestions = append(suggestions, suggestion{flagName: name, distance: distance})
+		}
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].flagName < suggestions[j].flagName
+	})
+	if len(suggestions) > maxSuggestionsCount {
+		suggestions = suggestions[:maxSuggestionsCount]
+	}
+	var res []string
+	for _, s := range suggestions {
+		res = append(res, s.flagName)
+	}
+	return res
+}
+
+// getDamerauLevenshteinDistance returns the number of insertions, deletions, substitutions and
+// transpositions of adjacent runes needed to transform `a` into `b` (optimal string alignment variant)
+func getDamerauLevenshteinDistance(a, b []rune) int {
+	// d[i][j] is the distance between a[:i] and b[:j]
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(first int, others ...int) int {
+	res := first
+	for _, v := range others {
+		if v < res {
+			res = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package flago
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownFlagSuggestions(t *testing.T) {
+	type nested struct {
+		Addr string `flag:"addr"`
+	}
+	type testStruct struct {
+		Verbose  bool   `flags:"verbose,v"`
+		Version  bool   `flag:"version"`
+		Versions bool   `flag:"versions"`
+		Secret   string `flag:"verbase" flagHidden:"true"`
+		Color    bool   `flags:"color,colour" flagDeprecatedAliases:"colour"`
+		Nested   nested `flagPrefix:"sender-"`
+	}
+	fls := NewFlagSet("", flag.ContinueOnError)
+	require.NoError(t, fls.StructVar(&testStruct{}))
+	parse := func(args ...string) (*UnknownFlagError, string) {
+		var err error
+		output := captureOutput(fls, func() {
+			err = fls.Parse(args)
+		})
+		var unknownFlagErr *UnknownFlagError
+		require.ErrorAs(t, err, &unknownFlagErr)
+		return unknownFlagErr, output
+	}
+
+	unknownFlagErr, _ := parse("--verbos")
+	require.Nil(t, unknownFlagErr.Suggestions)
+
+	fls.SetUnknownFlagSuggestions(true)
+	unknownFlagErr, output := parse("--verbos")
+	require.Equal(t, []string{"verbose"}, unknownFlagErr.Suggestions)
+	expectedMsg := "flag provided but not defined: -verbos (did you mean -verbose?)"
+	require.EqualError(t, unknownFlagErr, expectedMsg)
+	require.True(t, strings.HasPrefix(output, expectedMsg+"\n"))
+
+	unknownFlagErr, _ = parse("-sender-adr", "x")
+	require.Equal(t, []string{"sender-addr"}, unknownFlagErr.Suggestions)
+
+	unknownFlagErr, _ = parse("-colur")
+	require.Equal(t, []string{"color"}, unknownFlagErr.Suggestions)
+
+	unknownFlagErr, _ = parse("-versio")
+	require.Equal(t, []string{"version", "versions"}, unknownFlagErr.Suggestions)
+	require.EqualError(t, unknownFlagErr,
+		"flag provided but not defined: -versio (did you mean -version or -versions?)")
+
+	unknownFlagErr, _ = parse("-vrebose")
+	require.Equal(t, []string{"verbose"}, unknownFlagErr.Suggestions)
+
+	unknownFlagErr, _ = parse("-x")
+	require.Nil(t, unknownFlagErr.Suggestions)
+	require.EqualError(t, unknownFlagErr, "flag provided but not defined: -x")
+}
+
+func TestGetDamerauLevenshteinDistance(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"verbos", "verbose", 1},
+		{"vrebose", "verbose", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+		{"приве", "привет", 1},
+	} {
+		require.Equal(t, tc.distance, getDamerauLevenshteinDistance([]rune(tc.a), []rune(tc.b)), tc)
+		require.Equal(t, tc.distance, getDamerauLevenshteinDistance([]rune(tc.b), []rune(tc.a)), tc)
+	}
+}
@@ -0,0 +1,34 @@
+package flago
+
+import (
+	"reflect"
+
+	"github.com/cardinalby/go-struct-flags/cmdargs"
+)
+
+// setUnknownFlagsField assigns ignored flags to the field tagged with `flagUnknown`.
+// []string field gets args of the flags, map[string][]string field gets values of the flags by their names
+// ("true" for bool flags without values)
+func setUnknownFlagsField(fieldValue reflect.Value, entries []cmdargs.Entry) {
+	var value reflect.Value
+	if fieldValue.Kind() == reflect.Map {
+		flagValues := make(map[string][]string)
+		for _, entry := range entries {
+			if flagEntry, ok := entry.(cmdargs.FlagEntry); ok {
+				flagValue := flagEntry.Value()
+				if flagEntry.IsBool() && !flagEntry.IsInline() {
+					flagValue = "true"
+				}
+				flagValues[flagEntry.Name()] = append(flagValues[flagEntry.Name()], flagValue)
+			}
+		}
+		value = reflect.ValueOf(flagValues)
+	} else {
+		var args []string
+		for _, entry := range entries {
+			args = append(args, entry.TokenStrings()...)
+		}
+		value = reflect.ValueOf(args)
+	}
+	getAccessibleValue(fieldValue).Set(value.Convert(fieldValue.Type()))
+}
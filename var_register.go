@@ -71,6 +71,15 @@ func getVarRegister(fieldValue reflect.Value) (varRegister, error) {
 		}, nil
 	}
 
+	if valueType.Kind() == reflect.Slice {
+		if sv := newSliceValue(fieldValue); sv != nil {
+			return func(flagSet *flag.FlagSet, name, usage string) (postParseClb, bool) {
+				flagSet.Var(sv, name, usage)
+				return nil, fieldValue.Len() == 0
+			}, nil
+		}
+	}
+
 	return nil, fmt.Errorf("unsupported field type %s", valueType.Name())
 }
 